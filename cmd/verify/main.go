@@ -0,0 +1,53 @@
+// Command verify plays replay files without a window and checks that they still end as recorded.
+// It only needs the simulation, so it runs on machines without a display or a sound device.
+//
+//	go run ./cmd/verify replays/*.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"shooter/simulation"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: verify <replay file>...")
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		if err := verifyReplay(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Play the replay and report an error if it ends differently than recorded
+func verifyReplay(path string) error {
+	replay, err := simulation.LoadReplay(path)
+	if err != nil {
+		return err
+	}
+
+	if err := replay.Verify(); err != nil {
+		return fmt.Errorf("replay does not match: %w", err)
+	}
+
+	result := "lost"
+	if replay.Won {
+		result = "won"
+	}
+	fmt.Printf("%s: ok, %s after %d ticks with %d enemies destroyed\n", path, result, replay.Ticks, replay.EnemiesDestroyed)
+	return nil
+}
@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"math"
+	"shooter/simulation"
+)
+
+// Below this distance in a tick a player counts as standing still
+const walkThreshold = 0.1
+
+// actor is what the Game remembers about an entity of the World between ticks, to pick its animation
+type actor struct {
+	Animator
+	x, y   float64
+	health int
+	shots  int
+}
+
+// dyingEnemy is an enemy which left the World but still plays its death animation
+type dyingEnemy struct {
+	actor
+	enemy *simulation.Enemy
+}
+
+// Pick the animations from what changed in the World during the last tick and play them on
+func (g *Game) animate() {
+	w := g.World
+	dt := simulation.TickDuration
+
+	if w.Paused {
+		return
+	}
+
+	for i, p := range w.Players {
+		if i == len(g.playerActors) {
+			g.playerActors = append(g.playerActors, &actor{x: p.X, y: p.Y, health: p.Health, shots: p.Shots})
+		}
+		a := g.playerActors[i]
+
+		moved := math.Hypot(p.X-a.x, p.Y-a.y) > walkThreshold
+		switch {
+		case p.Shots != a.shots:
+			a.Restart(playerShoot)
+		case a.animation == playerShoot && !a.Done():
+			// Let the shot finish
+		case moved:
+			a.Play(playerWalk)
+		default:
+			a.Play(playerIdle)
+		}
+		if p.Health < a.health {
+			a.Flash()
+		}
+
+		a.x, a.y, a.health, a.shots = p.X, p.Y, p.Health, p.Shots
+		a.Update(dt)
+	}
+
+	// Enemies gone from the World were destroyed, they play their death before they vanish
+	seen := map[*simulation.Enemy]bool{}
+	for _, enm := range w.Enemies {
+		seen[enm] = true
+		a, ok := g.enemyActors[enm]
+		if !ok {
+			a = &actor{health: enm.Health}
+			a.Play(enemyWalk)
+			g.enemyActors[enm] = a
+		}
+		if enm.Health < a.health {
+			a.Flash()
+		}
+		a.health = enm.Health
+		a.Update(dt)
+	}
+	for enm, a := range g.enemyActors {
+		if !seen[enm] {
+			dying := &dyingEnemy{actor: *a, enemy: enm}
+			dying.Flash()
+			dying.Play(enemyDeath)
+			g.dyingEnemies = append(g.dyingEnemies, dying)
+			delete(g.enemyActors, enm)
+		}
+	}
+
+	dying := g.dyingEnemies[:0]
+	for _, d := range g.dyingEnemies {
+		d.Update(dt)
+		if !d.Done() {
+			dying = append(dying, d)
+		}
+	}
+	g.dyingEnemies = dying
+
+	// Only the flying projectiles are animated, the ones lying on the ground stay still
+	flying := make(map[*simulation.Projectile]*actor, len(g.projectileActors))
+	for _, prj := range w.Projectiles {
+		if !prj.Active {
+			continue
+		}
+		a, ok := g.projectileActors[prj]
+		if !ok {
+			a = &actor{}
+			a.Play(projectileFly)
+		}
+		a.Update(dt)
+		flying[prj] = a
+	}
+	g.projectileActors = flying
+}
+
+// Forget the actors of the round before, when a new one starts
+func (g *Game) resetActors() {
+	g.playerActors = nil
+	g.enemyActors = map[*simulation.Enemy]*actor{}
+	g.dyingEnemies = nil
+	g.projectileActors = map[*simulation.Projectile]*actor{}
+}
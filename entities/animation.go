@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Frame is one picture of an Animation: a sprite and how it is moved, stretched, turned and faded
+type Frame struct {
+	Sprite           string // Name in the atlas, the sprite of the entity when left out
+	Duration         time.Duration
+	OffsetX, OffsetY float64 // Pixels, the offset of a mirrored sprite is mirrored too
+	ScaleX, ScaleY   float64 // 0 counts as 1
+	Rotation         float64
+	Fade             float64 // From 0 for opaque to 1 for invisible
+}
+
+// Animation is a sequence of frames, played once or looped
+type Animation struct {
+	Frames []Frame
+	Loop   bool
+}
+
+// The animations of the entities. The sheets only have a single picture of every sprite, so the frames
+// make them come alive by moving the sprite around.
+var (
+	playerIdle = &Animation{Loop: true, Frames: []Frame{
+		{Duration: 400 * time.Millisecond},
+		{Duration: 400 * time.Millisecond, ScaleX: 1.03, ScaleY: 0.97, OffsetY: 0.5},
+	}}
+	playerWalk = &Animation{Loop: true, Frames: []Frame{
+		{Duration: 100 * time.Millisecond, Rotation: -0.08},
+		{Duration: 100 * time.Millisecond, OffsetY: -2},
+		{Duration: 100 * time.Millisecond, Rotation: 0.08},
+		{Duration: 100 * time.Millisecond, OffsetY: -2},
+	}}
+	playerShoot = &Animation{Frames: []Frame{
+		{Duration: 50 * time.Millisecond, OffsetX: 3, ScaleX: 0.9, ScaleY: 1.05},
+		{Duration: 80 * time.Millisecond, OffsetX: 1},
+	}}
+	enemyWalk = &Animation{Loop: true, Frames: []Frame{
+		{Duration: 150 * time.Millisecond, Rotation: -0.1},
+		{Duration: 150 * time.Millisecond, OffsetY: -1},
+		{Duration: 150 * time.Millisecond, Rotation: 0.1},
+		{Duration: 150 * time.Millisecond, OffsetY: -1},
+	}}
+	enemyDeath = &Animation{Frames: []Frame{
+		{Duration: 60 * time.Millisecond, ScaleX: 1.2, ScaleY: 0.8},
+		{Duration: 60 * time.Millisecond, ScaleX: 0.8, ScaleY: 0.6, Rotation: 0.4, Fade: 0.3},
+		{Duration: 60 * time.Millisecond, ScaleX: 0.5, ScaleY: 0.4, Rotation: 0.8, Fade: 0.6},
+		{Duration: 60 * time.Millisecond, ScaleX: 0.2, ScaleY: 0.2, Rotation: 1.2, Fade: 0.9},
+	}}
+	projectileFly = &Animation{Loop: true, Frames: []Frame{
+		{Duration: 80 * time.Millisecond},
+		{Duration: 80 * time.Millisecond, ScaleX: 1.15, ScaleY: 1.15},
+	}}
+)
+
+// A hit makes the sprite flash white for this long
+const flashDuration = 120 * time.Millisecond
+
+// Animator plays an Animation for a single entity, it can flash the sprite over any animation
+type Animator struct {
+	animation *Animation
+	elapsed   time.Duration
+	flash     time.Duration // Left of the flash
+}
+
+// Play the animation from its start, unless it is already playing
+func (a *Animator) Play(animation *Animation) {
+	if a.animation == animation {
+		return
+	}
+	a.animation = animation
+	a.elapsed = 0
+}
+
+// Restart plays the animation from its start even when it is already playing
+func (a *Animator) Restart(animation *Animation) {
+	a.animation = animation
+	a.elapsed = 0
+}
+
+// Flash the sprite white, like when it is hit
+func (a *Animator) Flash() {
+	a.flash = flashDuration
+}
+
+func (a *Animator) Update(dt time.Duration) {
+	a.elapsed += dt
+	a.flash = max(0, a.flash-dt)
+}
+
+// Done tells if an animation which is not looped has played all of its frames
+func (a *Animator) Done() bool {
+	return a.animation == nil || !a.animation.Loop && a.elapsed >= a.animation.length()
+}
+
+// Frame returns the frame to show now, a finished animation stays on its last frame
+func (a *Animator) Frame() Frame {
+	if a.animation == nil || len(a.animation.Frames) == 0 {
+		return Frame{}
+	}
+
+	elapsed := a.elapsed
+	if a.animation.Loop {
+		elapsed %= a.animation.length()
+	}
+	for _, f := range a.animation.Frames {
+		if elapsed < f.Duration {
+			return f
+		}
+		elapsed -= f.Duration
+	}
+	return a.animation.Frames[len(a.animation.Frames)-1]
+}
+
+func (anim *Animation) length() time.Duration {
+	var length time.Duration
+	for _, f := range anim.Frames {
+		length += f.Duration
+	}
+	return max(length, 1)
+}
+
+// Move, stretch and turn a sprite which has already been centered on the origin
+func (f Frame) apply(opts *ebiten.DrawImageOptions) {
+	scaleX, scaleY := f.ScaleX, f.ScaleY
+	if scaleX == 0 {
+		scaleX = 1
+	}
+	if scaleY == 0 {
+		scaleY = 1
+	}
+	opts.GeoM.Scale(scaleX, scaleY)
+	opts.GeoM.Rotate(f.Rotation)
+	opts.GeoM.Translate(f.OffsetX, f.OffsetY)
+	opts.ColorScale.ScaleAlpha(float32(1 - f.Fade))
+}
+
+// Draw the sprite, brightened over itself while it flashes
+func (a *Animator) draw(screen, img *ebiten.Image, opts *ebiten.DrawImageOptions) {
+	screen.DrawImage(img, opts)
+
+	if a.flash > 0 {
+		opts.Blend = ebiten.BlendLighter
+		opts.ColorScale.ScaleAlpha(float32(a.flash) / float32(flashDuration))
+		screen.DrawImage(img, opts)
+	}
+}
@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// The sounds and the font of the game, the sprites are given by the caller of LoadAssets
+//
+//go:embed sounds fonts
+var builtinFiles embed.FS
+
+const fontPath = "fonts/pressstart2p.ttf"
+
+// Assets are everything the images, the text and the sounds of a Game are made from
+type Assets struct {
+	Atlas *Atlas
+	Files fs.FS // With the sprites, sounds and fonts directories
+	font  *text.GoTextFaceSource
+}
+
+// LoadAssets reads the assets built into the game, the sprites from the given file system. Any file
+// of the override directory, if one is given, is used instead of the one at the same path.
+func LoadAssets(sprites fs.FS, override string) (*Assets, error) {
+	files := layeredFS{sprites, builtinFiles}
+	if override != "" {
+		if info, err := os.Stat(override); err != nil {
+			return nil, fmt.Errorf("assets: %w", err)
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("assets: %s is not a directory", override)
+		}
+		files = append(layeredFS{os.DirFS(override)}, files...)
+	}
+
+	atlas, err := LoadAtlas(files, AtlasPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fs.ReadFile(files, fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("font: %w", err)
+	}
+	font, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("font %s: %w", fontPath, err)
+	}
+
+	return &Assets{Atlas: atlas, Files: files, font: font}, nil
+}
+
+// layeredFS opens a file from the first layer which has it, directories list the files of all layers
+type layeredFS []fs.FS
+
+func (l layeredFS) Open(name string) (fs.File, error) {
+	for _, layer := range l {
+		f, err := layer.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (l layeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	found := false
+	for _, layer := range l {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+
+		// The upper layers come first, so a file they have hides the one below
+		for _, e := range layerEntries {
+			if !slices.ContainsFunc(entries, func(d fs.DirEntry) bool { return d.Name() == e.Name() }) {
+				entries = append(entries, e)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
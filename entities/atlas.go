@@ -0,0 +1,220 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"path"
+	"shooter/simulation"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Sprite is a picture cut from a sheet of the Atlas
+type Sprite struct {
+	Image            *ebiten.Image
+	AnchorX, AnchorY float64            // Pixel of the picture put on the position of the entity
+	Hitbox           *simulation.Hitbox // Used by enemy types which do not define their own
+	Tile             int                // Tile size of the sheet it was cut from
+}
+
+// Atlas gives the sprites by name, so nothing else needs to know where on which sheet they are
+type Atlas struct {
+	sprites map[string]*Sprite
+	missing *Sprite // Drawn for names which are not in the atlas, so they stand out
+}
+
+// The JSON manifest of an Atlas, sheet files are relative to it
+type atlasManifest struct {
+	Sheets  map[string]sheetEntry  `json:"sheets"`
+	Sprites map[string]spriteEntry `json:"sprites"`
+}
+
+type sheetEntry struct {
+	File string `json:"file"`
+	Tile int    `json:"tile"` // Width and height of a cell in pixels
+}
+
+type spriteEntry struct {
+	Sheet  string             `json:"sheet"`
+	Cell   *[2]int            `json:"cell,omitempty"`   // Column and row of a cell of the sheet
+	Rect   *[4]int            `json:"rect,omitempty"`   // X, y, width and height in pixels, instead of a cell
+	Anchor *[2]float64        `json:"anchor,omitempty"` // The center of the picture when left out
+	Hitbox *simulation.Hitbox `json:"hitbox,omitempty"`
+}
+
+// LoadAtlas reads the manifest from the file system and cuts all of its sprites from their sheets
+func LoadAtlas(files fs.FS, file string) (*Atlas, error) {
+	data, err := fs.ReadFile(files, file)
+	if err != nil {
+		return nil, fmt.Errorf("atlas: %w", err)
+	}
+
+	var m atlasManifest
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("atlas %s: %w", file, err)
+	}
+
+	// Load every sheet once, however many sprites are cut from it
+	var errs []error
+	sheets := map[string]*ebiten.Image{}
+	for _, name := range sortedKeys(m.Sheets) {
+		sh := m.Sheets[name]
+		if sh.Tile <= 0 {
+			errs = append(errs, fmt.Errorf("sheet %q: tile must be positive, got %d", name, sh.Tile))
+			continue
+		}
+		img, _, err := ebitenutil.NewImageFromFileSystem(files, path.Join(path.Dir(file), sh.File))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sheet %q: %w", name, err))
+			continue
+		}
+		sheets[name] = img
+	}
+
+	a := &Atlas{sprites: map[string]*Sprite{}, missing: missingSprite()}
+	for _, name := range sortedKeys(m.Sprites) {
+		s, err := m.cut(m.Sprites[name], sheets)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sprite %q: %w", name, err))
+			continue
+		}
+		a.sprites[name] = s
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("atlas %s: %w", file, err)
+	}
+	return a, nil
+}
+
+// Cut the sprite of an entry from its sheet
+func (m atlasManifest) cut(e spriteEntry, sheets map[string]*ebiten.Image) (*Sprite, error) {
+	sheet, ok := sheets[e.Sheet]
+	if !ok {
+		if _, listed := m.Sheets[e.Sheet]; listed {
+			return nil, fmt.Errorf("sheet %q could not be loaded", e.Sheet)
+		}
+		return nil, fmt.Errorf("unknown sheet %q", e.Sheet)
+	}
+	tile := m.Sheets[e.Sheet].Tile
+
+	var rect image.Rectangle
+	switch {
+	case e.Cell != nil && e.Rect != nil:
+		return nil, errors.New("cell and rect cannot both be given")
+	case e.Cell != nil:
+		x, y := e.Cell[0]*tile, e.Cell[1]*tile
+		rect = image.Rect(x, y, x+tile, y+tile)
+	case e.Rect != nil:
+		rect = image.Rect(e.Rect[0], e.Rect[1], e.Rect[0]+e.Rect[2], e.Rect[1]+e.Rect[3])
+	default:
+		return nil, errors.New("cell or rect must be given")
+	}
+	if rect.Empty() || !rect.In(sheet.Bounds()) {
+		return nil, fmt.Errorf("%v does not fit in the sheet %q of size %v", rect, e.Sheet, sheet.Bounds().Size())
+	}
+
+	s := &Sprite{
+		Image:   sheet.SubImage(rect).(*ebiten.Image),
+		AnchorX: float64(rect.Dx()) / 2,
+		AnchorY: float64(rect.Dy()) / 2,
+		Hitbox:  e.Hitbox,
+		Tile:    tile,
+	}
+	if e.Anchor != nil {
+		s.AnchorX, s.AnchorY = e.Anchor[0], e.Anchor[1]
+	}
+	if e.Hitbox != nil {
+		if err := e.Hitbox.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Sprite returns the sprite of the given name, names missing from the atlas get a placeholder
+func (a *Atlas) Sprite(name string) *Sprite {
+	if s, ok := a.sprites[name]; ok {
+		return s
+	}
+	return a.missing
+}
+
+// Check reports every name the game or the stages refer to which is not in the atlas
+func (a *Atlas) Check(stages simulation.StageSet) error {
+	var errs []error
+	for _, name := range spriteNames(stages) {
+		if _, ok := a.sprites[name]; !ok {
+			errs = append(errs, fmt.Errorf("no sprite named %q", name))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("atlas: %w", err)
+	}
+	return nil
+}
+
+// FillHitboxes gives the enemy types without a hitbox the one of their sprite, if it has one
+func (a *Atlas) FillHitboxes(stages simulation.StageSet) {
+	for name, t := range stages.Types {
+		if s := a.Sprite(t.Sprite); t.Hitbox == nil && s.Hitbox != nil {
+			t.Hitbox = s.Hitbox
+			stages.Types[name] = t
+		}
+	}
+}
+
+// Move the anchor of the sprite to the origin, so the entity can be rotated and placed around it
+func (s *Sprite) anchor(opts *ebiten.DrawImageOptions) {
+	opts.GeoM.Translate(-s.AnchorX, -s.AnchorY)
+}
+
+// Every name the game draws, whatever happens in the round
+func spriteNames(stages simulation.StageSet) []string {
+	names := []string{boltSprite, floorSprite}
+	names = append(names, playerSprites[:]...)
+	for _, name := range powerUpSprites {
+		names = append(names, name)
+	}
+	for _, name := range weaponSprites {
+		names = append(names, name)
+	}
+	for _, t := range stages.Types {
+		names = append(names, t.Sprite)
+	}
+	for _, anim := range []*Animation{playerIdle, playerWalk, playerShoot, enemyWalk, enemyDeath, projectileFly} {
+		for _, f := range anim.Frames {
+			if f.Sprite != "" {
+				names = append(names, f.Sprite)
+			}
+		}
+	}
+
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// A magenta square, nothing in the sheets looks like it
+func missingSprite() *Sprite {
+	img := ebiten.NewImage(spriteSize, spriteSize)
+	img.Fill(color.RGBA{255, 0, 255, 255})
+	return &Sprite{Image: img, AnchorX: spriteSize / 2, AnchorY: spriteSize / 2, Tile: spriteSize}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"fmt"
+	"shooter/simulation"
+)
+
+// Sound played for each event of the World, events without one are silent
+var eventSounds = map[simulation.Event]string{
+	simulation.EventShot:      "shot",
+	simulation.EventEnemyShot: "shot",
+	simulation.EventHit:       "hit",
+	simulation.EventDestroyed: "destroyed",
+	simulation.EventHurt:      "hurt",
+	simulation.EventPickup:    "pickup",
+	simulation.EventStage:     "stage",
+	simulation.EventBoss:      "stage",
+	simulation.EventGameOver:  "gameover",
+	simulation.EventWon:       "won",
+}
+
+// Volume is how loud the game is, every value goes from 0 to 1. Music and effects are scaled by the master volume.
+type Volume struct {
+	Master  float64
+	Music   float64
+	Effects float64
+}
+
+func DefaultVolume() Volume {
+	return Volume{Master: 1, Music: 0.5, Effects: 0.8}
+}
+
+func (v Volume) Validate() error {
+	for _, value := range []float64{v.Master, v.Music, v.Effects} {
+		if value < 0 || value > 1 {
+			return fmt.Errorf("volume must be between 0 and 1, got %v", value)
+		}
+	}
+	return nil
+}
+
+// soundBackend plays the sounds by their name, the name of a sound is its file name without the extension
+type soundBackend interface {
+	playEffect(name string, volume float64)
+	playMusic(name string, volume float64) // Loops the track until another one is played, "" stops the music
+	pauseMusic(paused bool)
+}
+
+// Audio plays a sound for the events of the World and loops the music of the current stage
+type Audio struct {
+	backend soundBackend
+	volume  Volume
+	music   string // Track playing now
+	paused  bool
+	played  map[string]bool // Sounds played during this tick, many hits at once only make one sound
+}
+
+// NewNullAudio returns an Audio which plays nothing, for running without a sound device
+func NewNullAudio() *Audio {
+	return &Audio{backend: nullBackend{}, played: map[string]bool{}}
+}
+
+// Update plays the sounds of the events of the last Step and changes the music with the stage
+func (a *Audio) Update(w *simulation.World) {
+	clear(a.played)
+	for _, e := range w.Events {
+		name, ok := eventSounds[e]
+		if !ok || a.played[name] {
+			continue
+		}
+		a.played[name] = true
+		a.backend.playEffect(name, a.volume.Master*a.volume.Effects)
+	}
+
+	// The music stops once the round is over
+	track := ""
+	if !w.GameOver {
+		track = stageMusic(w)
+	}
+	if track != a.music {
+		a.music = track
+		a.backend.playMusic(track, a.volume.Master*a.volume.Music)
+	}
+
+	if w.Paused != a.paused {
+		a.paused = w.Paused
+		a.backend.pauseMusic(a.paused)
+	}
+}
+
+// Stop the music, for when the round is left
+func (a *Audio) Stop() {
+	a.music = ""
+	a.backend.playMusic("", 0)
+}
+
+// Find the track of the current stage, a stage without one keeps the track of the stage before
+func stageMusic(w *simulation.World) string {
+	stages := w.Config.Stages.Stages
+	for i := w.Stage - 1; i >= 0; i-- {
+		if stages[i].Music != "" {
+			return stages[i].Music
+		}
+	}
+	return ""
+}
+
+// nullBackend plays nothing, so that the game runs without a sound device
+type nullBackend struct{}
+
+func (nullBackend) playEffect(name string, volume float64) {}
+func (nullBackend) playMusic(name string, volume float64)  {}
+func (nullBackend) pauseMusic(paused bool)                 {}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Draw the health of the boss at the top of the screen while it is alive
+func (g *Game) drawBossBar(screen *ebiten.Image) {
+	boss := g.World.Boss()
+	if boss == nil {
+		return
+	}
+
+	const barWidth, barHeight = 240, 10
+	h := g.hud
+
+	h.text(screen, strings.ToUpper(boss.Type), anchorTop, 0, hudMargin, textSize, color.White)
+
+	fraction := float64(boss.Health) / float64(boss.MaxHealth)
+	h.bar(screen, (ScreenWidth-barWidth)/2, hudMargin+textSize+4, barWidth, barHeight, fraction, color.RGBA{160, 32, 160, 255}, color.RGBA{64, 0, 0, 255})
+}
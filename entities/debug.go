@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"shooter/simulation"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The debug overlay is toggled with a fixed key on any screen, like the options are opened
+const debugKey = ebiten.KeyF3
+
+// Velocities are drawn as far as they carry in this many ticks, a single tick would be too short to see
+const velocityTicks = 10
+
+// Draw what the simulation sees over the World: the cells of the enemy grid, the hitboxes, the reach of
+// the enemies and where everything is heading
+func (g *Game) drawDebug(screen *ebiten.Image) {
+	w := g.World
+
+	// The more enemies in a cell, the darker it is
+	w.EnemyCells(func(x, y, size float64, count int) {
+		alpha := uint8(min(32*count, 160))
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(size), float32(size), color.RGBA{0, 0, alpha, alpha}, false)
+		vector.StrokeRect(screen, float32(x), float32(y), float32(size), float32(size), 1, color.RGBA{64, 64, 255, 255}, false)
+	})
+
+	for _, pck := range w.Pickups {
+		drawShape(screen, pck.Shape(), color.RGBA{0, 255, 255, 255})
+	}
+
+	for _, prj := range w.Projectiles {
+		drawShape(screen, prj.Shape(), color.RGBA{255, 255, 0, 255})
+		drawVelocity(screen, prj.X, prj.Y, prj.VelocityX, prj.VelocityY)
+	}
+
+	for _, enm := range w.Enemies {
+		vector.StrokeCircle(screen, float32(enm.X), float32(enm.Y), float32(enm.Reach), 1, color.RGBA{255, 128, 0, 255}, false)
+		drawShape(screen, enm.Shape(), color.RGBA{255, 0, 0, 255})
+		drawVelocity(screen, enm.X, enm.Y, enm.VelocityX, enm.VelocityY)
+	}
+
+	for _, p := range w.Players {
+		drawShape(screen, p.Shape(), color.RGBA{0, 255, 0, 255})
+	}
+}
+
+// Draw the speed of the game, the amount of things in the World and the time of the stage in the bottom right corner
+func (g *Game) drawDebugStats(screen *ebiten.Image) {
+	w := g.World
+	elapsed, duration := w.StageTime()
+
+	stats := fmt.Sprintf("TPS %.0f FPS %.0f\nEnemies %d\nProjectiles %d\nPickups %d\nStage %d %.1fs/%.0fs",
+		ebiten.ActualTPS(), ebiten.ActualFPS(), len(w.Enemies), len(w.Projectiles), len(w.Pickups), w.Stage, elapsed.Seconds(), duration.Seconds())
+	g.hud.text(screen, stats, anchorBottomRight, -hudMargin, -hudMargin, textSize, color.RGBA{255, 255, 0, 255})
+}
+
+// Outline a shape of the simulation
+func drawShape(screen *ebiten.Image, shape simulation.Shape, clr color.Color) {
+	switch s := shape.(type) {
+	case simulation.Circle:
+		vector.StrokeCircle(screen, float32(s.X), float32(s.Y), float32(s.Radius), 1, clr, false)
+	case simulation.Box:
+		vector.StrokeRect(screen, float32(s.X-s.HalfWidth), float32(s.Y-s.HalfHeight), float32(2*s.HalfWidth), float32(2*s.HalfHeight), 1, clr, false)
+	case simulation.OrientedBox:
+		// Turn the corners around the center and connect them
+		sin, cos := math.Sincos(s.Rotation)
+		var corners [4][2]float32
+		for i, c := range [4][2]float64{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+			x, y := c[0]*s.HalfWidth, c[1]*s.HalfHeight
+			corners[i] = [2]float32{float32(s.X + x*cos - y*sin), float32(s.Y + x*sin + y*cos)}
+		}
+		for i, from := range corners {
+			to := corners[(i+1)%len(corners)]
+			vector.StrokeLine(screen, from[0], from[1], to[0], to[1], 1, clr, false)
+		}
+	}
+}
+
+func drawVelocity(screen *ebiten.Image, x, y, velocityX, velocityY float64) {
+	if velocityX == 0 && velocityY == 0 {
+		return
+	}
+	endX, endY := x+velocityX*velocityTicks, y+velocityY*velocityTicks
+	vector.StrokeLine(screen, float32(x), float32(y), float32(endX), float32(endY), 1, color.White, false)
+}
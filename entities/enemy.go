@@ -1,47 +1,52 @@
 package entities
 
 import (
-	"math"
+	"image/color"
+	"shooter/simulation"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-type Enemy struct {
-	img   *ebiten.Image
-	reach float64
-	x, y  float64
-}
-
-func (enm *Enemy) Update(p *Player) {
-	// Calculate the difference in position
-	dx := (p.X - spriteSize/2) - enm.x
-	dy := (p.Y - spriteSize/2) - enm.y
+// Length of the line showing the aim of an enemy about to shoot
+const aimLineLength = 3 * spriteSize
 
-	// Calculate the distance to the destination point (enemy to player)
-	distance := math.Sqrt(dx*dx + dy*dy)
-
-	// If the image is very close to the center, stop moving
-	if distance < enm.reach {
-		gameOver = true
-		return
+func (g *Game) drawEnemy(screen *ebiten.Image, enm *simulation.Enemy) {
+	a, ok := g.enemyActors[enm]
+	if !ok {
+		a = &actor{}
 	}
+	g.drawEnemySprite(screen, enm, &a.Animator)
 
-	// Normalize the direction vector (up to 1)
-	dirX := dx / distance
-	dirY := dy / distance
-
-	// Move the image towards the center
-	enm.x += dirX * maxEnemySpeed
-	enm.y += dirY * maxEnemySpeed
+	// Show where the enemy is about to shoot
+	if x, y, ok := enm.Aim(); ok {
+		startX, startY := float32(enm.X), float32(enm.Y)
+		endX, endY := startX+float32(x*aimLineLength), startY+float32(y*aimLineLength)
+		vector.StrokeLine(screen, startX, startY, endX, endY, 1, color.RGBA{255, 64, 64, 160}, false)
+	}
 }
 
-func (enm *Enemy) Draw(screen *ebiten.Image) {
+// Draw the sprite of the enemy in the frame of its animation, also used for the ones which are dying
+func (g *Game) drawEnemySprite(screen *ebiten.Image, enm *simulation.Enemy, a *Animator) {
 	// Create a new DrawImageOptions struct
 	opts := &ebiten.DrawImageOptions{}
+	frame := a.Frame()
 
-	// Set the position of the image
-	opts.GeoM.Translate(enm.x, enm.y)
+	// Set the position of the image, the enemy position is its center. A boss is drawn bigger.
+	sprite := g.Atlas.Sprite(enm.Sprite)
+	if frame.Sprite != "" {
+		sprite = g.Atlas.Sprite(frame.Sprite)
+	}
+	sprite.anchor(opts)
+	frame.apply(opts)
+	opts.GeoM.Scale(enm.Scale, enm.Scale)
+	opts.GeoM.Translate(enm.X, enm.Y)
+
+	// Show that a dash is coming
+	if enm.WindingUp() {
+		opts.ColorScale.Scale(1, 0.4, 0.4, 1)
+	}
 
 	// Draw the image to the screen with the scaling options
-	screen.DrawImage(enm.img, opts)
+	a.draw(screen, sprite.Image, opts)
 }
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"image/color"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// gameOverScene is shown once the round is over, it tells a victory from a defeat
+type gameOverScene struct {
+	won bool
+}
+
+func (s *gameOverScene) Update(g *Game) Transition {
+	switch {
+	case g.controls.JustPressed(ActionRestart):
+		// Create "new" Game instance, by resetting variables
+		g.ResetGame()
+		return switchTo(&playingScene{})
+	case g.controls.JustPressed(ActionQuit):
+		return quit()
+	}
+	return stay()
+}
+
+func (s *gameOverScene) Draw(g *Game, screen *ebiten.Image) {
+	h := g.hud
+	g.drawBackground(screen)
+	g.drawHUD(screen)
+
+	heading := "Game over! You've just got gobbled!"
+	if s.won {
+		heading = "YOU WIN! :D"
+	}
+	h.text(screen, heading, anchorCenter, 0, -3*titleSize, titleSize, color.White)
+
+	// The seed lets anyone play this very round again
+	menu := g.controls.Describe(ActionRestart) + " to Restart\n" +
+		g.controls.Describe(ActionQuit) + " to Quit\n\n" +
+		"Seed: " + strconv.FormatInt(g.World.Seed, 10)
+	h.text(screen, menu, anchorCenter, 0, titleSize, textSize, color.White)
+}
@@ -0,0 +1,166 @@
+package entities
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"shooter/simulation"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	textSize      = 8  // Size of the regular text in pixels of the game
+	titleSize     = 16 // Size of the headings
+	lineSpacing   = 1.5
+	hudMargin     = 4
+	hudIconSize   = 16
+	hudBarWidth   = 60
+	hudBarHeight  = 6
+	hudRowHeight  = 26 // Space taken by the status of a single player
+	hudRoundLines = 4  // Lines of the round status in the top right corner
+)
+
+// Anchor is the point of the screen a HUD element is placed against, the element is aligned to the same side
+type anchor int
+
+const (
+	anchorTopLeft anchor = iota
+	anchorTop
+	anchorTopRight
+	anchorCenter
+	anchorBottomLeft
+	anchorBottom
+	anchorBottomRight
+)
+
+// Tell where the anchor is on the screen of the game and how the text is aligned to it
+func (a anchor) point() (x, y float64, alignX, alignY text.Align) {
+	switch a {
+	case anchorTop:
+		return ScreenWidth / 2, 0, text.AlignCenter, text.AlignStart
+	case anchorTopRight:
+		return ScreenWidth, 0, text.AlignEnd, text.AlignStart
+	case anchorCenter:
+		return ScreenWidth / 2, ScreenHeight / 2, text.AlignCenter, text.AlignCenter
+	case anchorBottomLeft:
+		return 0, ScreenHeight, text.AlignStart, text.AlignEnd
+	case anchorBottom:
+		return ScreenWidth / 2, ScreenHeight, text.AlignCenter, text.AlignEnd
+	case anchorBottomRight:
+		return ScreenWidth, ScreenHeight, text.AlignEnd, text.AlignEnd
+	}
+	return 0, 0, text.AlignStart, text.AlignStart
+}
+
+// hud draws the text and the status of the round over the game. Everything is placed in pixels of the game
+// and drawn at the size of the window, so that the text stays sharp however big the window is.
+type hud struct {
+	font    *text.GoTextFaceSource
+	scale   float64 // Pixels of the window for a pixel of the game
+	boltImg *ebiten.Image
+}
+
+func newHUD(assets *Assets) *hud {
+	return &hud{
+		font:    assets.font,
+		scale:   1,
+		boltImg: assets.Atlas.Sprite(boltSprite).Image,
+	}
+}
+
+// Draw the status of every player in the top left corner, the round in the top right one and the boss on top
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	h := g.hud
+	w := g.World
+
+	for i, p := range w.Players {
+		g.drawPlayerStatus(screen, p, i, hudMargin+float64(i)*hudRowHeight)
+	}
+
+	// The stage, the score and the time of the round, the world stops counting the time when paused or over
+	secondsPassed := int(math.Round(w.Elapsed.Seconds()))
+	displayTime := fmt.Sprintf("%dh %dm %ds", secondsPassed/3600%24, secondsPassed/60%60, secondsPassed%60)
+	round := fmt.Sprintf("Stage %d/%d\nScore %d\nKills %d\n%s", w.Stage, len(w.Config.Stages.Stages), w.Score, w.EnemiesDestroyed, displayTime)
+	h.text(screen, round, anchorTopRight, -hudMargin, hudMargin, textSize, color.White)
+
+	g.drawEffects(screen, hudMargin+hudRoundLines*textSize*lineSpacing+hudMargin)
+	g.drawBossBar(screen)
+
+	// Keyboard is always available, gamepads can be used along with it
+	gamepadsConnected := "Gamepads connected: " + strconv.Itoa(g.controls.GamepadCount())
+	h.text(screen, gamepadsConnected, anchorBottomLeft, hudMargin, -hudMargin, textSize, color.White)
+}
+
+// Draw the health, the bolts and the weapon of a player in a row
+func (g *Game) drawPlayerStatus(screen *ebiten.Image, p *simulation.Player, index int, y float64) {
+	h := g.hud
+	w := g.World
+	x := float64(hudMargin)
+
+	// Name the player when more of them play
+	if len(w.Players) > 1 {
+		h.text(screen, fmt.Sprintf("P%d", index+1), anchorTopLeft, x, y+hudIconSize/2-textSize/2, textSize, color.White)
+		x += 3 * textSize
+	}
+
+	// No health bar is needed when any hit ends the game
+	if !w.Config.OneHit {
+		fraction := float64(p.Health) / float64(w.Config.PlayerHealth)
+		h.bar(screen, x, y+(hudIconSize-hudBarHeight)/2, hudBarWidth, hudBarHeight, fraction, color.RGBA{200, 32, 32, 255}, color.RGBA{64, 0, 0, 255})
+		x += hudBarWidth + hudMargin
+	}
+
+	h.image(screen, h.boltImg, x, y, hudIconSize)
+	bolts := fmt.Sprintf("%d/%d", p.BoltAmount, p.BoltCapacity)
+	h.text(screen, bolts, anchorTopLeft, x+hudIconSize+2, y+hudIconSize/2-textSize/2, textSize, color.White)
+
+	h.text(screen, weaponStatus(p), anchorTopLeft, hudMargin, y+hudIconSize+1, textSize, color.RGBA{200, 200, 200, 255})
+}
+
+// Draw the text next to the anchor, moved by dx and dy. Lines are split on "\n".
+func (h *hud) text(screen *ebiten.Image, str string, a anchor, dx, dy, size float64, clr color.Color) {
+	x, y, alignX, alignY := a.point()
+	face := &text.GoTextFace{Source: h.font, Size: size * h.scale}
+
+	opts := &text.DrawOptions{}
+	opts.PrimaryAlign = alignX
+	opts.SecondaryAlign = alignY
+	opts.LineSpacing = size * lineSpacing * h.scale
+
+	// A dark shadow keeps the text readable on any background
+	opts.GeoM.Translate((x+dx+1)*h.scale, (y+dy+1)*h.scale)
+	opts.ColorScale.ScaleWithColor(color.Black)
+	text.Draw(screen, str, face, opts)
+
+	opts.GeoM.Translate(-h.scale, -h.scale)
+	opts.ColorScale.Reset()
+	opts.ColorScale.ScaleWithColor(clr)
+	text.Draw(screen, str, face, opts)
+}
+
+// Draw an image with its top left corner at x, y, size pixels of the game wide
+func (h *hud) image(screen, img *ebiten.Image, x, y, size float64) {
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(size/float64(img.Bounds().Dx())*h.scale, size/float64(img.Bounds().Dy())*h.scale)
+	opts.GeoM.Translate(x*h.scale, y*h.scale)
+	screen.DrawImage(img, opts)
+}
+
+// Draw a bar filled from the left up to the fraction, with a frame around it
+func (h *hud) bar(screen *ebiten.Image, x, y, width, height, fraction float64, fill, empty color.Color) {
+	s := h.scale
+	fraction = min(max(fraction, 0), 1)
+	vector.DrawFilledRect(screen, float32(x*s), float32(y*s), float32(width*s), float32(height*s), empty, false)
+	vector.DrawFilledRect(screen, float32(x*s), float32(y*s), float32(width*fraction*s), float32(height*s), fill, false)
+	vector.StrokeRect(screen, float32(x*s), float32(y*s), float32(width*s), float32(height*s), float32(s), color.White, false)
+}
+
+// Darken the whole screen, for menus shown over the game
+func (h *hud) dim(screen *ebiten.Image, alpha uint8) {
+	bounds := screen.Bounds()
+	vector.DrawFilledRect(screen, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), color.RGBA{0, 0, 0, alpha}, false)
+}
@@ -0,0 +1,494 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Action is something the player can do, independent of the key or button it is bound to
+type Action string
+
+const (
+	ActionMoveUp    Action = "move_up"
+	ActionMoveDown  Action = "move_down"
+	ActionMoveLeft  Action = "move_left"
+	ActionMoveRight Action = "move_right"
+	ActionAimUp     Action = "aim_up"
+	ActionAimDown   Action = "aim_down"
+	ActionAimLeft   Action = "aim_left"
+	ActionAimRight  Action = "aim_right"
+	ActionFire      Action = "fire"
+	ActionPause     Action = "pause"
+	ActionRestart   Action = "restart"
+	ActionQuit      Action = "quit"
+)
+
+// Actions lists all the actions in the order they are shown to the player
+var Actions = []Action{
+	ActionMoveUp, ActionMoveDown, ActionMoveLeft, ActionMoveRight,
+	ActionAimUp, ActionAimDown, ActionAimLeft, ActionAimRight,
+	ActionFire, ActionPause, ActionRestart, ActionQuit,
+}
+
+// Sticks are ignored below this value on each axis, so that a worn stick does not move the player
+const stickDeadZone = 0.1
+
+// A stick has to be pushed this far to be bound in the options
+const stickCaptureThreshold = 0.5
+
+// Bindings map actions to keys and gamepad buttons. Move and aim can also be bound to a gamepad stick.
+type Bindings struct {
+	Keys      map[Action][]ebiten.Key    `json:"keys"`
+	Buttons   map[Action][]GamepadButton `json:"buttons"`
+	MoveStick *GamepadStick              `json:"move_stick"` // null for no stick
+	AimStick  *GamepadStick              `json:"aim_stick"`
+}
+
+func DefaultBindings() Bindings {
+	return Bindings{
+		Keys: map[Action][]ebiten.Key{
+			ActionMoveUp:    {ebiten.KeyW},
+			ActionMoveDown:  {ebiten.KeyS},
+			ActionMoveLeft:  {ebiten.KeyA},
+			ActionMoveRight: {ebiten.KeyD},
+			ActionAimUp:     {ebiten.KeyArrowUp},
+			ActionAimDown:   {ebiten.KeyArrowDown},
+			ActionAimLeft:   {ebiten.KeyArrowLeft},
+			ActionAimRight:  {ebiten.KeyArrowRight},
+			ActionFire:      {ebiten.KeySpace},
+			ActionPause:     {ebiten.KeyP},
+			ActionRestart:   {ebiten.KeyR},
+			ActionQuit:      {ebiten.KeyQ},
+		},
+		Buttons: map[Action][]GamepadButton{
+			ActionFire:    {{Standard: ebiten.StandardGamepadButtonFrontBottomRight}},
+			ActionPause:   {{Standard: ebiten.StandardGamepadButtonCenterLeft}},
+			ActionRestart: {{Standard: ebiten.StandardGamepadButtonCenterLeft}},
+			ActionQuit:    {{Standard: ebiten.StandardGamepadButtonCenterRight}},
+		},
+		MoveStick: &GamepadStick{Standard: leftStick},
+		AimStick:  &GamepadStick{Standard: rightStick},
+	}
+}
+
+// LoadBindings reads a JSON bindings file over the default bindings, so the file only needs the actions
+// it changes. The default bindings are returned if the file does not exist.
+func LoadBindings(path string) (Bindings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultBindings(), nil
+	}
+	if err != nil {
+		return Bindings{}, fmt.Errorf("bindings: %w", err)
+	}
+
+	// Actions and sticks left out of the file keep their default bindings
+	b := DefaultBindings()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return Bindings{}, fmt.Errorf("bindings %s: %w", path, err)
+	}
+	if err := b.Validate(); err != nil {
+		return Bindings{}, fmt.Errorf("bindings %s: %w", path, err)
+	}
+
+	return b, nil
+}
+
+func (b Bindings) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "\t")
+	if err != nil {
+		return fmt.Errorf("bindings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("bindings: %w", err)
+	}
+	return nil
+}
+
+// Validate reports actions which do not exist
+func (b Bindings) Validate() error {
+	var errs []error
+
+	known := map[Action]bool{}
+	for _, a := range Actions {
+		known[a] = true
+	}
+	for a := range b.Keys {
+		if !known[a] {
+			errs = append(errs, fmt.Errorf("keys: unknown action %q", a))
+		}
+	}
+	for a := range b.Buttons {
+		if !known[a] {
+			errs = append(errs, fmt.Errorf("buttons: unknown action %q", a))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Controls turn the state of the keyboard and of all the connected gamepads into actions.
+// Every device can be used at the same time.
+type Controls struct {
+	Bindings      Bindings
+	gamepadIDsBuf []ebiten.GamepadID
+	gamepadIDs    []ebiten.GamepadID // In the order they were connected
+}
+
+// DeviceSet reads the actions from a part of the devices only, for example from the ones of a single player
+type DeviceSet struct {
+	controls *Controls
+	keyboard bool
+	gamepads []ebiten.GamepadID
+}
+
+func NewControls(b Bindings) *Controls {
+	// Keep the maps ready for the binding menu to add to
+	if b.Keys == nil {
+		b.Keys = map[Action][]ebiten.Key{}
+	}
+	if b.Buttons == nil {
+		b.Buttons = map[Action][]GamepadButton{}
+	}
+
+	return &Controls{
+		Bindings: b,
+	}
+}
+
+// Update keeps track of the connected gamepads, call it once per tick before reading any action
+func (c *Controls) Update() {
+	c.gamepadIDs = slices.DeleteFunc(c.gamepadIDs, inpututil.IsGamepadJustDisconnected)
+
+	c.gamepadIDsBuf = inpututil.AppendJustConnectedGamepadIDs(c.gamepadIDsBuf[:0])
+	c.gamepadIDs = append(c.gamepadIDs, c.gamepadIDsBuf...)
+}
+
+func (c *Controls) GamepadCount() int {
+	return len(c.gamepadIDs)
+}
+
+// All reads the actions from every device
+func (c *Controls) All() DeviceSet {
+	return DeviceSet{controls: c, keyboard: true, gamepads: c.gamepadIDs}
+}
+
+// ForPlayer returns the devices of one of the players. Gamepads are dealt to the players in the order
+// they were connected and the keyboard belongs to the first player without a gamepad, or to the first player.
+func (c *Controls) ForPlayer(player, players int) DeviceSet {
+	set := DeviceSet{controls: c}
+
+	for i, id := range c.gamepadIDs {
+		if i%players == player {
+			set.gamepads = append(set.gamepads, id)
+		}
+	}
+
+	keyboardPlayer := min(len(c.gamepadIDs), players)
+	if keyboardPlayer == players {
+		keyboardPlayer = 0
+	}
+	set.keyboard = player == keyboardPlayer
+
+	return set
+}
+
+// JustPressed reports whether the action was just pressed on any device
+func (c *Controls) JustPressed(a Action) bool {
+	return c.All().JustPressed(a)
+}
+
+// Pressed reports whether any key or button bound to the action is held
+func (d DeviceSet) Pressed(a Action) bool {
+	b := d.controls.Bindings
+
+	if d.keyboard {
+		for _, key := range b.Keys[a] {
+			if ebiten.IsKeyPressed(key) {
+				return true
+			}
+		}
+	}
+	for _, id := range d.gamepads {
+		for _, button := range b.Buttons[a] {
+			if button.pressed(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// JustPressed reports whether any key or button bound to the action was pressed in this tick
+func (d DeviceSet) JustPressed(a Action) bool {
+	b := d.controls.Bindings
+
+	if d.keyboard {
+		for _, key := range b.Keys[a] {
+			if inpututil.IsKeyJustPressed(key) {
+				return true
+			}
+		}
+	}
+	for _, id := range d.gamepads {
+		for _, button := range b.Buttons[a] {
+			if button.justPressed(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Move returns the movement direction, each axis in range -1 to 1
+func (d DeviceSet) Move() (x, y float64) {
+	x, y = d.direction(ActionMoveUp, ActionMoveDown, ActionMoveLeft, ActionMoveRight)
+
+	if stick := d.controls.Bindings.MoveStick; stick != nil {
+		for _, id := range d.gamepads {
+			sx, sy := stick.value(id)
+			x += sx
+			y += sy
+		}
+	}
+
+	return clamp(x), clamp(y)
+}
+
+// Aim returns the aiming angle, or false if nothing is aiming in this tick
+func (d DeviceSet) Aim() (angle float64, ok bool) {
+	// The stick wins over the keys since it is more precise
+	if stick := d.controls.Bindings.AimStick; stick != nil {
+		for _, id := range d.gamepads {
+			if x, y := stick.value(id); x != 0 || y != 0 {
+				return math.Atan2(y, x), true
+			}
+		}
+	}
+
+	x, y := d.direction(ActionAimUp, ActionAimDown, ActionAimLeft, ActionAimRight)
+	if x != 0 || y != 0 {
+		return math.Atan2(y, x), true
+	}
+
+	return 0, false
+}
+
+// Describe lists everything bound to the action, to be shown to the player
+func (c *Controls) Describe(a Action) string {
+	var names []string
+	for _, key := range c.Bindings.Keys[a] {
+		names = append(names, key.String())
+	}
+	for _, button := range c.Bindings.Buttons[a] {
+		names = append(names, button.String())
+	}
+
+	if len(names) == 0 {
+		return "(unbound)"
+	}
+	return strings.Join(names, " / ")
+}
+
+// Combine four direction actions into a vector
+func (d DeviceSet) direction(up, down, left, right Action) (x, y float64) {
+	if d.Pressed(up) {
+		y -= 1
+	}
+	if d.Pressed(down) {
+		y += 1
+	}
+	if d.Pressed(left) {
+		x -= 1
+	}
+	if d.Pressed(right) {
+		x += 1
+	}
+	return x, y
+}
+
+func clamp(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+// GamepadButton is a button of the standard gamepad layout, or a raw button for gamepads without one.
+// In the bindings file it is written like "FrontBottomRight" or "Button6".
+type GamepadButton struct {
+	Standard ebiten.StandardGamepadButton
+	Raw      ebiten.GamepadButton
+	IsRaw    bool
+}
+
+var standardButtonNames = map[ebiten.StandardGamepadButton]string{
+	ebiten.StandardGamepadButtonRightBottom:      "RightBottom",
+	ebiten.StandardGamepadButtonRightRight:       "RightRight",
+	ebiten.StandardGamepadButtonRightLeft:        "RightLeft",
+	ebiten.StandardGamepadButtonRightTop:         "RightTop",
+	ebiten.StandardGamepadButtonFrontTopLeft:     "FrontTopLeft",
+	ebiten.StandardGamepadButtonFrontTopRight:    "FrontTopRight",
+	ebiten.StandardGamepadButtonFrontBottomLeft:  "FrontBottomLeft",
+	ebiten.StandardGamepadButtonFrontBottomRight: "FrontBottomRight",
+	ebiten.StandardGamepadButtonCenterLeft:       "CenterLeft",
+	ebiten.StandardGamepadButtonCenterRight:      "CenterRight",
+	ebiten.StandardGamepadButtonLeftStick:        "LeftStick",
+	ebiten.StandardGamepadButtonRightStick:       "RightStick",
+	ebiten.StandardGamepadButtonLeftTop:          "LeftTop",
+	ebiten.StandardGamepadButtonLeftBottom:       "LeftBottom",
+	ebiten.StandardGamepadButtonLeftLeft:         "LeftLeft",
+	ebiten.StandardGamepadButtonLeftRight:        "LeftRight",
+	ebiten.StandardGamepadButtonCenterCenter:     "CenterCenter",
+}
+
+func (b GamepadButton) pressed(id ebiten.GamepadID) bool {
+	if b.IsRaw {
+		return ebiten.IsGamepadButtonPressed(id, b.Raw)
+	}
+	// Triggers are analog, so anything past the dead zone counts as pressed
+	return ebiten.StandardGamepadButtonValue(id, b.Standard) > stickDeadZone
+}
+
+func (b GamepadButton) justPressed(id ebiten.GamepadID) bool {
+	if b.IsRaw {
+		return inpututil.IsGamepadButtonJustPressed(id, b.Raw)
+	}
+	return inpututil.IsStandardGamepadButtonJustPressed(id, b.Standard)
+}
+
+func (b GamepadButton) String() string {
+	if b.IsRaw {
+		return fmt.Sprintf("Button%d", b.Raw)
+	}
+	return standardButtonNames[b.Standard]
+}
+
+func (b GamepadButton) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+func (b *GamepadButton) UnmarshalText(text []byte) error {
+	name := string(text)
+
+	var raw int
+	if _, err := fmt.Sscanf(name, "Button%d", &raw); err == nil {
+		*b = GamepadButton{Raw: ebiten.GamepadButton(raw), IsRaw: true}
+		return nil
+	}
+	for button, buttonName := range standardButtonNames {
+		if buttonName == name {
+			*b = GamepadButton{Standard: button}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown gamepad button %q", name)
+}
+
+// GamepadStick is a stick of the standard gamepad layout, or a pair of raw axes for gamepads without one.
+// In the bindings file it is written like "LeftStick", "RightStick" or "Axes:0,1".
+type GamepadStick struct {
+	Standard     int // One of leftStick or rightStick
+	AxisX, AxisY int
+	IsRaw        bool
+}
+
+const (
+	leftStick = iota
+	rightStick
+)
+
+func (s GamepadStick) value(id ebiten.GamepadID) (x, y float64) {
+	switch {
+	case s.IsRaw:
+		x = ebiten.GamepadAxisValue(id, s.AxisX)
+		y = ebiten.GamepadAxisValue(id, s.AxisY)
+	case s.Standard == leftStick:
+		x = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+		y = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+	default:
+		x = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickHorizontal)
+		y = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickVertical)
+	}
+
+	if math.Abs(x) <= stickDeadZone {
+		x = 0
+	}
+	if math.Abs(y) <= stickDeadZone {
+		y = 0
+	}
+	return x, y
+}
+
+// The stick binding of the given row of the stickRows in the options
+func (b *Bindings) stick(row int) **GamepadStick {
+	if row == 0 {
+		return &b.MoveStick
+	}
+	return &b.AimStick
+}
+
+// pushedStick returns the stick of the gamepad which is pushed far. Gamepads without the standard layout
+// have their raw axes paired up as they usually come: 0 and 1, 2 and 3 and so on.
+func pushedStick(id ebiten.GamepadID) (GamepadStick, bool) {
+	var sticks []GamepadStick
+	if ebiten.IsStandardGamepadLayoutAvailable(id) {
+		sticks = []GamepadStick{{Standard: leftStick}, {Standard: rightStick}}
+	} else {
+		for axis := 0; axis+1 < ebiten.GamepadAxisCount(id); axis += 2 {
+			sticks = append(sticks, GamepadStick{AxisX: axis, AxisY: axis + 1, IsRaw: true})
+		}
+	}
+
+	for _, s := range sticks {
+		if x, y := s.value(id); math.Hypot(x, y) > stickCaptureThreshold {
+			return s, true
+		}
+	}
+	return GamepadStick{}, false
+}
+
+func (s GamepadStick) String() string {
+	switch {
+	case s.IsRaw:
+		return fmt.Sprintf("Axes:%d,%d", s.AxisX, s.AxisY)
+	case s.Standard == leftStick:
+		return "LeftStick"
+	default:
+		return "RightStick"
+	}
+}
+
+func (s GamepadStick) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *GamepadStick) UnmarshalText(text []byte) error {
+	name := string(text)
+
+	switch name {
+	case "LeftStick":
+		*s = GamepadStick{Standard: leftStick}
+		return nil
+	case "RightStick":
+		*s = GamepadStick{Standard: rightStick}
+		return nil
+	}
+
+	var x, y int
+	if _, err := fmt.Sscanf(name, "Axes:%d,%d", &x, &y); err == nil {
+		*s = GamepadStick{AxisX: x, AxisY: y, IsRaw: true}
+		return nil
+	}
+
+	return fmt.Errorf("unknown gamepad stick %q", name)
+}
@@ -0,0 +1,174 @@
+package entities
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// The options are operated with fixed keys, so that the player can never lock themselves out of them
+const optionsKey = ebiten.KeyF1
+
+// The sticks are listed after the actions, they are bound by pushing a stick of a gamepad
+var stickRows = []string{"move_stick", "aim_stick"}
+
+// optionsScene lets the player change the Bindings, it is opened from the title screen or while the game is paused
+type optionsScene struct {
+	selected  int  // Index in Actions, followed by the stickRows
+	capturing bool // Waiting for a key or button to bind to the selected action, or for a stick
+	keysBuf   []ebiten.Key
+	buttonBuf []ebiten.GamepadButton
+	stdBuf    []ebiten.StandardGamepadButton
+}
+
+func (m *optionsScene) Update(g *Game) Transition {
+	c := g.controls
+	rows := len(Actions) + len(stickRows)
+
+	if m.capturing {
+		m.captureBinding(g)
+		return stay()
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(optionsKey) || inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.saveBindings()
+		return pop()
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		m.selected = (m.selected + rows - 1) % rows
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		m.selected = (m.selected + 1) % rows
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		m.capturing = true
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) || inpututil.IsKeyJustPressed(ebiten.KeyDelete):
+		// Remove everything bound to the action, or the stick
+		if m.selected >= len(Actions) {
+			*c.Bindings.stick(m.selected - len(Actions)) = nil
+			break
+		}
+		action := Actions[m.selected]
+		delete(c.Bindings.Keys, action)
+		delete(c.Bindings.Buttons, action)
+	}
+	return stay()
+}
+
+func (m *optionsScene) Draw(g *Game, screen *ebiten.Image) {
+	h := g.hud
+	x, y := 60.0, 60.0
+	rowHeight := textSize * lineSpacing
+
+	// Darken the game behind the menu
+	h.dim(screen, 192)
+
+	h.text(screen, "Controls", anchorTopLeft, x, y, titleSize, color.White)
+	y += 2 * titleSize
+
+	for i, action := range Actions {
+		m.drawRow(g, screen, i, string(action)+": "+g.controls.Describe(action), x, y+float64(i)*rowHeight)
+	}
+	for i, name := range stickRows {
+		row := len(Actions) + i
+		stick := "(unbound)"
+		if s := *g.controls.Bindings.stick(i); s != nil {
+			stick = s.String()
+		}
+		m.drawRow(g, screen, row, name+": "+stick, x, y+float64(row)*rowHeight)
+	}
+
+	help := "Up/Down select, Enter add, Backspace clear, F1 close"
+	switch {
+	case m.capturing && m.selected >= len(Actions):
+		help = "Push a stick, Escape to cancel"
+	case m.capturing:
+		help = "Press a key or button, Escape to cancel"
+	}
+	h.text(screen, help, anchorBottom, 0, -4*textSize, textSize, color.White)
+}
+
+// Draw a line of the menu, the selected one is marked
+func (m *optionsScene) drawRow(g *Game, screen *ebiten.Image, row int, line string, x, y float64) {
+	clr := color.RGBA{200, 200, 200, 255}
+	if row == m.selected {
+		line = "> " + line
+		clr = color.RGBA{255, 255, 255, 255}
+	} else {
+		line = "  " + line
+	}
+	g.hud.text(screen, line, anchorTopLeft, x, y, textSize, clr)
+}
+
+// Bind the first key or gamepad button pressed to the selected action, or the first stick pushed to the selected stick
+func (m *optionsScene) captureBinding(g *Game) {
+	b := &g.controls.Bindings
+	if m.selected >= len(Actions) {
+		m.captureStick(g)
+		return
+	}
+	action := Actions[m.selected]
+
+	m.keysBuf = inpututil.AppendJustPressedKeys(m.keysBuf[:0])
+	for _, key := range m.keysBuf {
+		if key != ebiten.KeyEscape {
+			b.Keys[action] = appendUnique(b.Keys[action], key)
+		}
+		m.capturing = false
+		return
+	}
+
+	for _, id := range g.controls.gamepadIDs {
+		// Prefer the standard layout, raw buttons are kept for gamepads without one
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			m.stdBuf = inpututil.AppendJustPressedStandardGamepadButtons(id, m.stdBuf[:0])
+			for _, button := range m.stdBuf {
+				b.Buttons[action] = appendUnique(b.Buttons[action], GamepadButton{Standard: button})
+				m.capturing = false
+				return
+			}
+			continue
+		}
+
+		m.buttonBuf = inpututil.AppendJustPressedGamepadButtons(id, m.buttonBuf[:0])
+		for _, button := range m.buttonBuf {
+			b.Buttons[action] = appendUnique(b.Buttons[action], GamepadButton{Raw: button, IsRaw: true})
+			m.capturing = false
+			return
+		}
+	}
+}
+
+func (m *optionsScene) captureStick(g *Game) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		m.capturing = false
+		return
+	}
+
+	for _, id := range g.controls.gamepadIDs {
+		if s, ok := pushedStick(id); ok {
+			*g.controls.Bindings.stick(m.selected - len(Actions)) = &s
+			m.capturing = false
+			return
+		}
+	}
+}
+
+// Write the bindings back to the file they were loaded from, a failure does not stop the game
+func (g *Game) saveBindings() {
+	if g.bindingsPath == "" {
+		return
+	}
+	if err := g.controls.Bindings.Save(g.bindingsPath); err != nil {
+		log.Println(err)
+	}
+}
+
+func appendUnique[T comparable](list []T, item T) []T {
+	for _, existing := range list {
+		if existing == item {
+			return list
+		}
+	}
+	return append(list, item)
+}
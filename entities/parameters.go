@@ -1,35 +1,18 @@
 package entities
 
 import (
-	"time"
+	"shooter/simulation"
 )
 
 const (
-	ScreenHeight = 480
-	ScreenWidth  = 640
+	ScreenHeight = simulation.ScreenHeight
+	ScreenWidth  = simulation.ScreenWidth
 
-	CharacterSpriteSheetPath = "sprites/rogues.png"
-	MonsterSpriteSheetPath   = "sprites/monsters.png"
-	ItemSpriteSheetPath      = "sprites/items.png"
-	TileSpriteSheetPath      = "sprites/tiles.png"
+	AtlasPath = "sprites/atlas.json"
 
-	PlayerSpeed       = 2
-	InitialBoltAmount = 10
-	rotationSpeed     = 0.03
-	ProjectileSpeed   = 10
-	StageDuration     = 10 // Seconds
+	boltSprite  = "item.bolt"   // Projectiles and the bolts in the HUD
+	floorSprite = "tile.floors" // Every row of its tiles is a pattern for the background
 
-	maxEnemySpeed = 0.75
-	SpawnInterval = 1
-
-	spriteSize = 32
-)
-
-var (
-	startTime     = time.Now() // As far as i know this has to be here to happen only once
-	pauseStart    = time.Now()
-	pauseDuration time.Duration
-	displayTime   = "" // Keep it as a global variable so that we can display it after the game is over
-	gameOver      = false
-	gamePaused    = false
+	spriteSize = 32   // The size of a tile of the game, the sprites themselves may be of any size
+	minScale   = 0.25 // The window can be made this much smaller than the game
 )
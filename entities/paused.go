@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// pausedScene is shown over the round while it is paused
+type pausedScene struct{}
+
+func (s *pausedScene) Update(g *Game) Transition {
+	switch {
+	case inpututil.IsKeyJustPressed(optionsKey):
+		return push(&optionsScene{})
+	case g.controls.JustPressed(ActionQuit):
+		// Give up the round and go back to the title
+		g.ResetGame()
+		return switchTo(&titleScene{})
+	}
+
+	// The World keeps stepping, so that unpausing is read (and recorded) like any other input
+	g.step()
+	switch {
+	case g.World.GameOver:
+		// The round can end on the tick it is unpaused, it must not be stepped (and saved) again by the playing scene
+		return switchTo(&gameOverScene{won: g.World.Won()})
+	case !g.World.Paused:
+		return pop()
+	}
+	return stay()
+}
+
+func (s *pausedScene) Draw(g *Game, screen *ebiten.Image) {
+	h := g.hud
+	h.dim(screen, 96)
+
+	h.text(screen, "Game Paused", anchorCenter, 0, -2*titleSize, titleSize, color.White)
+
+	menu := g.controls.Describe(ActionPause) + " to resume\n" +
+		"F1 to change controls\n" +
+		g.controls.Describe(ActionQuit) + " to go back to the title"
+	h.text(screen, menu, anchorCenter, 0, titleSize, textSize, color.White)
+}
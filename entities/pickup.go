@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"fmt"
+	"image/color"
+	"shooter/simulation"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Sprites in the atlas, one for each power up
+var powerUpSprites = map[simulation.PowerUp]string{
+	simulation.PowerUpMultishot: "item.short_bow",
+	simulation.PowerUpPiercing:  "item.pike",
+	simulation.PowerUpSpeed:     "item.blue_boots",
+	simulation.PowerUpShield:    "item.heater_shield",
+	simulation.PowerUpRefill:    "item.red_potion",
+	simulation.PowerUpCapacity:  "item.tome",
+}
+
+// Pickups about to vanish blink for this many seconds
+const pickupBlinkTime = 2
+
+func (g *Game) drawPickup(screen *ebiten.Image, pck *simulation.Pickup) {
+	// Blink a few times per second before vanishing
+	remaining := pck.Remaining.Seconds()
+	if remaining < pickupBlinkTime && int(remaining*6)%2 == 0 {
+		return
+	}
+
+	// Create a new DrawImageOptions struct
+	opts := &ebiten.DrawImageOptions{}
+
+	sprite := g.Atlas.Sprite(powerUpSprites[pck.PowerUp])
+	if pck.Weapon != "" {
+		sprite = g.Atlas.Sprite(weaponSprites[pck.Weapon])
+	}
+
+	// Pickups are placed by their anchor
+	sprite.anchor(opts)
+	opts.GeoM.Translate(pck.X, pck.Y)
+	screen.DrawImage(sprite.Image, opts)
+}
+
+// Draw the active power ups of every player as a row of icons with the seconds left under them, in the top right corner
+func (g *Game) drawEffects(screen *ebiten.Image, top float64) {
+	h := g.hud
+	iconSize := float64(spriteSize * 3 / 4)
+	rowHeight := iconSize + 2*textSize
+
+	for i, p := range g.World.Players {
+		y := top + float64(i)*rowHeight
+		x := ScreenWidth - hudMargin - iconSize
+
+		for j := len(p.Effects) - 1; j >= 0; j-- {
+			eff := p.Effects[j]
+
+			h.image(screen, g.Atlas.Sprite(powerUpSprites[eff.PowerUp]).Image, x, y, iconSize)
+
+			label := fmt.Sprintf("%ds", int(eff.Remaining.Seconds()+0.999))
+			h.text(screen, label, anchorTopLeft, x, y+iconSize+2, textSize, color.White)
+
+			x -= iconSize + 8
+		}
+
+		// Name the player when more of them play
+		if len(g.World.Players) > 1 && len(p.Effects) > 0 {
+			h.text(screen, fmt.Sprintf("P%d", i+1), anchorTopLeft, x+iconSize-2*textSize, y+iconSize/2-textSize/2, textSize, color.White)
+		}
+	}
+}
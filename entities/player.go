@@ -2,27 +2,58 @@ package entities
 
 import (
 	"math"
+	"shooter/simulation"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-type Player struct {
-	LoadTime       time.Time
-	BoltAmount     int
-	BoltShotBefore bool
-	X, Y           float64 // These address the CENTER of an image
-	Rotation       float64
-	Img            *ebiten.Image // New field to store the loaded image
+// Sprites in the atlas, one for each player
+var playerSprites = [simulation.MaxPlayers]string{"rogue.bandit", "rogue.ranger", "rogue.elf", "rogue.dwarf"}
+
+// readPlayerInputs translates the actions bound to the devices of every player into simulation input
+func (g *Game) readPlayerInputs() []simulation.Input {
+	players := len(g.World.Players)
+	inputs := make([]simulation.Input, players)
+
+	for i := range inputs {
+		d := g.controls.ForPlayer(i, players)
+		in := &inputs[i]
+
+		in.MoveX, in.MoveY = d.Move()
+		in.Aim, in.Aiming = d.Aim()
+		in.Fire = d.Pressed(ActionFire)
+
+		// "JustPressed", so that it will not toggle every tick the button is held
+		in.Pause = d.JustPressed(ActionPause)
+	}
+
+	return inputs
 }
 
-// In the Draw method of the Player struct
-func (p *Player) Draw(screen *ebiten.Image) {
+// Invulnerable players blink with this period
+const hurtBlink = 200 * time.Millisecond
+
+// Draw the Player, mirrored according to its rotation. Caught players are drawn faded, hurt ones blink.
+func (g *Game) drawPlayer(screen *ebiten.Image, p *simulation.Player, index int) {
 	// Create a new DrawImageOptions struct
 	opts := &ebiten.DrawImageOptions{}
 
-	// Translate to the center of the image before rotating
-	opts.GeoM.Translate(-spriteSize/2, -spriteSize/2)
+	a := &Animator{}
+	if index < len(g.playerActors) {
+		a = &g.playerActors[index].Animator
+	}
+	frame := a.Frame()
+
+	// Translate to the anchor of the image before rotating
+	sprite := g.Atlas.Sprite(playerSprites[index])
+	if frame.Sprite != "" {
+		sprite = g.Atlas.Sprite(frame.Sprite)
+	}
+	sprite.anchor(opts)
+
+	// Animate before mirroring, so the animation follows the way the player faces
+	frame.apply(opts)
 
 	// Mirror image
 	currentAngle := math.Abs(math.Mod(p.Rotation, 2*math.Pi))
@@ -32,107 +63,12 @@ func (p *Player) Draw(screen *ebiten.Image) {
 
 	opts.GeoM.Translate(p.X, p.Y)
 
-	// Draw the Player to the screen with the rotation options
-	screen.DrawImage(p.Img, opts)
-}
-
-// Shoot method of the Player struct
-func (p *Player) Shoot(g *Game) {
-	// Calculate the velocity based on the Player's rotation
-	vx := math.Cos(p.Rotation) * ProjectileSpeed
-	vy := math.Sin(p.Rotation) * ProjectileSpeed
-
-	// Create a new projectile with the calculated velocity
-	proj := &Projectile{
-		x:         p.X,
-		y:         p.Y,
-		rotation:  p.Rotation,
-		velocityX: vx,
-		velocityY: vy,
-		active:    true,
-		img:       g.ProjectileImg,
+	if p.Caught {
+		opts.ColorScale.ScaleAlpha(0.3)
+	} else if p.Invulnerable > 0 && p.Invulnerable%hurtBlink < hurtBlink/2 {
+		opts.ColorScale.ScaleAlpha(0.3)
 	}
 
-	g.Projectiles = append(g.Projectiles, proj)
-}
-
-// Update method of the Player struct
-func (p *Player) Update(g *Game) {
-	// We assume one player, so id=0. For more it would be 1, then 2 and so on
-	if len(g.gamepadIDs) != 0 {
-		for id := range g.gamepadIDs {
-			LSH := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
-			LSV := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
-			RSH := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickHorizontal)
-			RSV := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickVertical)
-
-			// RIght stick is aiming //to modify
-			p.Rotation = math.Atan2(RSV, RSH)
-
-			// Left stick is movement
-			if math.Abs(LSH) > 0.1 {
-				p.X += LSH * PlayerSpeed
-			}
-			if math.Abs(LSV) > 0.1 {
-				p.Y += LSV * PlayerSpeed
-			}
-
-			// Handle Player shooting (enable one shot at a time and only if player has bolts available)
-			FBR := ebiten.StandardGamepadButtonValue(id, ebiten.StandardGamepadButtonFrontBottomRight)
-			BoltToBeShotNow := math.Abs(FBR) > 0.1
-			if BoltToBeShotNow && !p.BoltShotBefore && p.BoltAmount > 0 {
-				p.BoltShotBefore = true
-				g.Player.Shoot(g)
-				p.removeBolt()
-			}
-			p.BoltShotBefore = BoltToBeShotNow
-		}
-	} else {
-		// Update player's rotation based on user input
-		if ebiten.IsKeyPressed(ebiten.KeyLeft) {
-			p.Rotation = math.Pi
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyRight) {
-			p.Rotation = 0
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyUp) {
-			p.Rotation = math.Pi * 1.5
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyDown) {
-			p.Rotation = math.Pi * 0.5
-		}
-
-		// WSAD keys control movement
-		if ebiten.IsKeyPressed(ebiten.KeyW) {
-			p.Y -= PlayerSpeed
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyS) {
-			p.Y += PlayerSpeed
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyA) {
-			p.X -= PlayerSpeed
-		}
-		if ebiten.IsKeyPressed(ebiten.KeyD) {
-			p.X += PlayerSpeed
-		}
-
-		// Handle Player shooting (enable one shot at a time and only if player has bolts available)
-		BoltToBeShotNow := ebiten.IsKeyPressed(ebiten.KeySpace)
-		if BoltToBeShotNow && !p.BoltShotBefore && p.BoltAmount > 0 {
-			p.BoltShotBefore = true
-			g.Player.Shoot(g)
-			p.removeBolt()
-		}
-		p.BoltShotBefore = BoltToBeShotNow
-	}
-}
-
-func (p *Player) addBolt() {
-	if p.BoltAmount < InitialBoltAmount {
-		p.BoltAmount += 1
-	}
-}
-
-func (p *Player) removeBolt() {
-	p.BoltAmount -= 1
+	// Draw the Player to the screen with the rotation options
+	a.draw(screen, sprite.Image, opts)
 }
@@ -0,0 +1,22 @@
+package entities
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// playingScene runs the round, it hands over to the pause menu or the end of the round once the World says so
+type playingScene struct{}
+
+func (s *playingScene) Update(g *Game) Transition {
+	g.step()
+
+	switch {
+	case g.World.GameOver:
+		return switchTo(&gameOverScene{won: g.World.Won()})
+	case g.World.Paused:
+		return push(&pausedScene{})
+	}
+	return stay()
+}
+
+func (s *playingScene) Draw(g *Game, screen *ebiten.Image) {
+	g.drawWorld(screen)
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"shooter/simulation"
+	"time"
+)
+
+// NewReplayGame prepares a Game which plays the recorded round instead of reading the user input
+func NewReplayGame(assets *Assets, replay *simulation.Replay) *Game {
+	g := NewGame(assets, replay.Config, replay.Seed)
+	g.replay = replay
+	g.World = simulation.NewReplayWorld(replay)
+	g.playback = simulation.NewPlayback(replay)
+
+	// A replay starts right away, there is nothing to choose on the title screen
+	g.scenes = sceneStack{scenes: []Scene{&playingScene{}}}
+
+	return g
+}
+
+// RecordReplays makes the Game write a replay file of every finished round into the given directory
+func (g *Game) RecordReplays(dir string) {
+	g.replayDir = dir
+	g.recorder = simulation.NewRecorder(g.World)
+}
+
+// nextInputs returns the input of all players for this tick, either from the user or from the replay, and records it
+func (g *Game) nextInputs() []simulation.Input {
+	inputs := g.readPlayerInputs()
+	if g.playback != nil {
+		// Once the recording ends nothing is pressed anymore
+		inputs, _ = g.playback.Next()
+	}
+
+	if g.recorder != nil {
+		g.recorder.Record(inputs)
+	}
+
+	return inputs
+}
+
+// saveReplay writes the replay of the round which has just ended, a failure does not stop the game
+func (g *Game) saveReplay() {
+	if g.recorder == nil {
+		return
+	}
+
+	replay := g.recorder.Finish(g.World)
+	name := fmt.Sprintf("replay-%s-%d.json", time.Now().Format("20060102-150405"), replay.Seed)
+	if err := replay.Save(filepath.Join(g.replayDir, name)); err != nil {
+		log.Println(err)
+	}
+}
+
+// restartReplay plays the recorded round again from the start
+func (g *Game) restartReplay() {
+	g.World = simulation.NewReplayWorld(g.replay)
+	g.playback = simulation.NewPlayback(g.replay)
+}
@@ -0,0 +1,73 @@
+package entities
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Scene is one screen of the game, it reads its own input and tells the Game where to go next
+type Scene interface {
+	Update(g *Game) Transition
+	Draw(g *Game, screen *ebiten.Image)
+}
+
+// Transition is returned by a Scene to change the scenes, the zero value stays in the current one
+type Transition struct {
+	kind  transitionKind
+	scene Scene
+}
+
+type transitionKind int
+
+const (
+	transitionStay     transitionKind = iota
+	transitionPush                    // Open the scene over the current one, like a menu
+	transitionPop                     // Close the current scene and go back to the one below
+	transitionSwitchTo                // Leave all the scenes for a new one, with a fade
+	transitionQuit                    // Close the game
+)
+
+func stay() Transition            { return Transition{} }
+func push(s Scene) Transition     { return Transition{kind: transitionPush, scene: s} }
+func pop() Transition             { return Transition{kind: transitionPop} }
+func switchTo(s Scene) Transition { return Transition{kind: transitionSwitchTo, scene: s} }
+func quit() Transition            { return Transition{kind: transitionQuit} }
+
+// A switch fades in the new scene for this long
+const fadeTicks = 20
+
+// sceneStack holds the open scenes, only the top one is updated but all of them are drawn from the bottom up,
+// so that a menu is shown over the game it was opened from
+type sceneStack struct {
+	scenes []Scene
+	fade   int // Ticks left of the fade after a switch
+}
+
+func (s *sceneStack) Update(g *Game) error {
+	s.fade = max(0, s.fade-1)
+
+	t := s.top().Update(g)
+	switch t.kind {
+	case transitionPush:
+		s.scenes = append(s.scenes, t.scene)
+	case transitionPop:
+		s.scenes = s.scenes[:len(s.scenes)-1]
+	case transitionSwitchTo:
+		s.scenes = []Scene{t.scene}
+		s.fade = fadeTicks
+	case transitionQuit:
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (s *sceneStack) Draw(g *Game, screen *ebiten.Image) {
+	for _, scene := range s.scenes {
+		scene.Draw(g, screen)
+	}
+
+	if s.fade > 0 {
+		g.hud.dim(screen, uint8(255*s.fade/fadeTicks))
+	}
+}
+
+func (s *sceneStack) top() Scene {
+	return s.scenes[len(s.scenes)-1]
+}
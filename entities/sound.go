@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+const sampleRate = 44100
+
+// NewAudio returns an Audio playing the sounds of the assets on the sound device. The sound effects
+// and the music are WAV or OGG files in the sounds directory, named after the sound.
+func NewAudio(assets *Assets, volume Volume) (*Audio, error) {
+	if err := volume.Validate(); err != nil {
+		return nil, err
+	}
+
+	backend, err := newEbitenBackend(assets.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Audio{backend: backend, volume: volume, played: map[string]bool{}}, nil
+}
+
+// ebitenBackend plays the sounds with the ebiten audio package, they are all decoded once at the start
+type ebitenBackend struct {
+	context *audio.Context
+	sounds  map[string][]byte // Decoded samples of every sound by its name
+	music   *audio.Player     // Player of the track playing now
+	warned  map[string]bool   // Missing sounds are only reported once
+}
+
+func newEbitenBackend(files fs.FS) (*ebitenBackend, error) {
+	// There is only one audio context, which games created before already made
+	context := audio.CurrentContext()
+	if context == nil {
+		context = audio.NewContext(sampleRate)
+	}
+
+	b := &ebitenBackend{context: context, sounds: map[string][]byte{}, warned: map[string]bool{}}
+
+	entries, err := fs.ReadDir(files, "sounds")
+	if err != nil {
+		return nil, fmt.Errorf("sounds: %w", err)
+	}
+	for _, entry := range entries {
+		file := path.Join("sounds", entry.Name())
+		samples, err := decodeSound(files, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		b.sounds[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = samples
+	}
+
+	return b, nil
+}
+
+// Decode a WAV or OGG file into samples at the rate of the audio context
+func decodeSound(files fs.FS, file string) ([]byte, error) {
+	data, err := fs.ReadFile(files, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var stream io.Reader
+	switch path.Ext(file) {
+	case ".wav":
+		stream, err = wav.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
+	case ".ogg":
+		stream, err = vorbis.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unknown sound format %q", path.Ext(file))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(stream)
+}
+
+func (b *ebitenBackend) playEffect(name string, volume float64) {
+	samples, ok := b.sound(name)
+	if !ok {
+		return
+	}
+
+	p := b.context.NewPlayerFromBytes(samples)
+	p.SetVolume(volume)
+	p.Play()
+}
+
+func (b *ebitenBackend) playMusic(name string, volume float64) {
+	if b.music != nil {
+		b.music.Close()
+		b.music = nil
+	}
+	if name == "" {
+		return
+	}
+
+	samples, ok := b.sound(name)
+	if !ok {
+		return
+	}
+
+	loop := audio.NewInfiniteLoop(bytes.NewReader(samples), int64(len(samples)))
+	p, err := b.context.NewPlayer(loop)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	p.SetVolume(volume)
+	p.Play()
+	b.music = p
+}
+
+func (b *ebitenBackend) pauseMusic(paused bool) {
+	if b.music == nil {
+		return
+	}
+	if paused {
+		b.music.Pause()
+	} else {
+		b.music.Play()
+	}
+}
+
+func (b *ebitenBackend) sound(name string) ([]byte, bool) {
+	samples, ok := b.sounds[name]
+	if !ok && !b.warned[name] {
+		b.warned[name] = true
+		log.Printf("sound %q not found", name)
+	}
+	return samples, ok
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// titleScene is shown when the game starts, the round begins once the player is ready
+type titleScene struct{}
+
+func (s *titleScene) Update(g *Game) Transition {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || g.controls.JustPressed(ActionPause):
+		return switchTo(&playingScene{})
+	case inpututil.IsKeyJustPressed(optionsKey):
+		return push(&optionsScene{})
+	case g.controls.JustPressed(ActionQuit):
+		return quit()
+	}
+	return stay()
+}
+
+func (s *titleScene) Draw(g *Game, screen *ebiten.Image) {
+	h := g.hud
+	g.drawBackground(screen)
+
+	h.text(screen, "SHOOT THEM!", anchorCenter, 0, -3*titleSize, titleSize, color.White)
+
+	menu := "Enter or " + g.controls.Describe(ActionPause) + " to start\n" +
+		"F1 to change controls\n" +
+		g.controls.Describe(ActionQuit) + " to quit"
+	h.text(screen, menu, anchorCenter, 0, titleSize, textSize, color.White)
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"shooter/simulation"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Sprites in the atlas, one for each weapon
+var weaponSprites = map[simulation.WeaponKind]string{
+	simulation.WeaponCrossbow: "item.crossbow",
+	simulation.WeaponBow:      "item.long_bow",
+	simulation.WeaponStaff:    "item.sapphire_staff",
+	simulation.WeaponSword:    "item.short_sword",
+}
+
+// The sword sprite points to the top right corner of its image
+const weaponSpriteAngle = -math.Pi / 4
+
+// Describe the weapon and its ammo for the HUD
+func weaponStatus(p *simulation.Player) string {
+	wp := p.Weapon()
+	if wp.Ammo != simulation.AmmoQuiver {
+		return string(wp.Kind)
+	}
+	if p.Magazine == 0 {
+		return string(wp.Kind) + " reloading"
+	}
+	return fmt.Sprintf("%s %d/%d", wp.Kind, p.Magazine, wp.Magazine)
+}
+
+// Draw a swing sweeping across its arc and the charge of a shot being held
+func (g *Game) drawWeapon(screen *ebiten.Image, p *simulation.Player) {
+	if p.Caught {
+		return
+	}
+	wp := p.Weapon()
+
+	if p.Swing > 0 {
+		// The swing goes from one side of the arc to the other
+		angle := p.Rotation - wp.Arc/2 + wp.Arc*p.SwingProgress()
+
+		sprite := g.Atlas.Sprite(weaponSprites[wp.Kind])
+		opts := &ebiten.DrawImageOptions{}
+		sprite.anchor(opts)
+		opts.GeoM.Rotate(angle - weaponSpriteAngle)
+		opts.GeoM.Translate(p.X+math.Cos(angle)*wp.Reach/2, p.Y+math.Sin(angle)*wp.Reach/2)
+		screen.DrawImage(sprite.Image, opts)
+	}
+
+	if p.Charge > 0 {
+		const barWidth, barHeight = spriteSize, 3
+		x := float32(p.X - barWidth/2)
+		y := float32(p.Y + spriteSize/2 + 2)
+		vector.DrawFilledRect(screen, x, y, barWidth, barHeight, color.RGBA{32, 32, 64, 255}, false)
+		vector.DrawFilledRect(screen, x, y, barWidth*float32(p.Charge), barHeight, color.RGBA{96, 160, 255, 255}, false)
+	}
+}
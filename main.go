@@ -1,36 +1,112 @@
 package main
 
 import (
+	"embed"
+	"flag"
 	"log"
 	"shooter/entities"
-	"time"
+	"shooter/simulation"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// The sprites are built into the game, so it runs from any directory. -assets can replace any of them.
+//
+//go:embed sprites
+var sprites embed.FS
+
 func main() {
+	configPath := flag.String("config", "", "JSON file with balance values, built-in defaults are used when empty")
+	stagesPath := flag.String("stages", "", "JSON file with stage definitions, built-in stages are used when empty")
+	seed := flag.Int64("seed", 0, "Seed of the random number generator, every round gets a new one when 0")
+	recordDir := flag.String("record", "", "Directory to write a replay file of every finished round into")
+	replayPath := flag.String("replay", "", "Replay file to play back instead of reading the user input")
+	players := flag.Int("players", 0, "Amount of players playing together, overrides the config when set")
+	assetsDir := flag.String("assets", "", "Directory with files used instead of the built-in sprites, sounds and fonts at the same paths")
+	bindingsPath := flag.String("bindings", "bindings.json", "JSON file with the controls, changes made in game are saved to it")
+	volume := flag.Float64("volume", entities.DefaultVolume().Master, "Master volume from 0 to 1")
+	musicVolume := flag.Float64("music-volume", entities.DefaultVolume().Music, "Volume of the music from 0 to 1, scaled by the master volume")
+	effectsVolume := flag.Float64("effects-volume", entities.DefaultVolume().Effects, "Volume of the sound effects from 0 to 1, scaled by the master volume")
+	mute := flag.Bool("mute", false, "Play without sound, no sound device is needed")
+	flag.Parse()
+
 	// If importing from subdirectory they have to have first letter capitalized
-	tileSheet, enemySheet, projectileSheet, playerSheet := entities.LoadSpriteSheets()
-
-	game := &entities.Game{
-		SpawnTime:     time.Now(),
-		BackgroundImg: entities.GenerateBackground(tileSheet),
-		EnemyImg:      entities.AddBoundingBox(entities.LoadSpriteFromSheet(enemySheet, 0, 2)),
-		EnemySheet:    enemySheet,
-		ProjectileImg: entities.AddBoundingBox(entities.LoadSpriteFromSheet(projectileSheet, 0, 6)),
-		Player: &entities.Player{
-			BoltAmount: entities.InitialBoltAmount,
-			LoadTime:   time.Now(),
-			X:          entities.ScreenWidth / 2,
-			Y:          entities.ScreenHeight / 2,
-			Img:        entities.AddBoundingBox(entities.LoadSpriteFromSheet(playerSheet, 4, 0)),
-		},
-		Stage: 1,
+	assets, err := entities.LoadAssets(sprites, *assetsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var game *entities.Game
+	if *replayPath != "" {
+		replay, err := simulation.LoadReplay(*replayPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// The hitboxes of the sprites were filled in when the round was recorded, they are not taken from the atlas again
+		if err := assets.Atlas.Check(replay.Stages); err != nil {
+			log.Fatal(err)
+		}
+		game = entities.NewReplayGame(assets, replay)
+	} else {
+		cfg := loadConfig(*configPath, *stagesPath)
+		if *players != 0 {
+			cfg.Players = *players
+			if err := cfg.Validate(); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		// Every sprite the stages name has to be in the atlas, enemies without a hitbox take the one of their sprite.
+		// This happens before the World is made, so the replays hold the hitboxes and never depend on the atlas.
+		if err := assets.Atlas.Check(cfg.Stages); err != nil {
+			log.Fatal(err)
+		}
+		assets.Atlas.FillHitboxes(cfg.Stages)
+		game = entities.NewGame(assets, cfg, *seed)
+	}
+
+	bindings, err := entities.LoadBindings(*bindingsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	game.SetBindings(bindings, *bindingsPath)
+
+	if !*mute {
+		audio, err := entities.NewAudio(assets, entities.Volume{Master: *volume, Music: *musicVolume, Effects: *effectsVolume})
+		if err != nil {
+			log.Fatal(err)
+		}
+		game.SetAudio(audio)
+	}
+
+	if *recordDir != "" {
+		game.RecordReplays(*recordDir)
 	}
 
 	ebiten.SetWindowSize(entities.ScreenWidth, entities.ScreenHeight)
 	ebiten.SetWindowTitle("Shoot them!")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// Load the config and stage files, built-in values are used for the ones not given
+func loadConfig(configPath, stagesPath string) simulation.Config {
+	cfg := simulation.DefaultConfig()
+	if configPath != "" {
+		var err error
+		cfg, err = simulation.LoadConfig(configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if stagesPath != "" {
+		var err error
+		cfg.Stages, err = simulation.LoadStages(stagesPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	return cfg
+}
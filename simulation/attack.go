@@ -0,0 +1,124 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Attack lets an enemy shoot at the players. Before every shot the enemy stops and aims for a moment,
+// the direction is fixed when the aiming starts, so a player who sees it coming can step aside.
+type Attack struct {
+	Interval float64 `json:"interval"` // Seconds between shots
+	Windup   float64 `json:"windup"`   // Seconds the enemy aims before it shoots
+	Range    float64 `json:"range"`    // Pixels, the enemy only starts aiming at players closer than this
+	Speed    float64 `json:"speed"`    // Pixels per tick
+	Damage   int     `json:"damage"`   // 0 counts as 1
+	Shots    int     `json:"shots"`    // Shots fired at once in a fan, 0 counts as 1
+	Spread   float64 `json:"spread"`   // Radians between the shots of the fan
+	Bounces  int     `json:"bounces"`  // Times a shot bounces off the edges of the screen
+}
+
+// Count down to the next shot, returns true while the enemy is aiming
+func (enm *Enemy) updateAttack(w *World, dx, dy, distance, scale float64) bool {
+	a := enm.Attack
+	enm.attackTicks += scale
+
+	if !enm.aiming {
+		if enm.attackTicks >= a.Interval*TickRate && distance < a.Range {
+			enm.aiming = true
+			enm.attackTicks = 0
+			enm.shotX, enm.shotY = dx/distance, dy/distance
+		}
+		return false
+	}
+
+	if enm.attackTicks >= a.Windup*TickRate {
+		enm.shoot(w)
+		w.emit(EventEnemyShot)
+		enm.aiming = false
+		enm.attackTicks = 0
+	}
+	return true
+}
+
+func (enm *Enemy) shoot(w *World) {
+	damage := enm.Attack.Damage
+	if damage == 0 {
+		damage = 1
+	}
+
+	shots := max(1, enm.Attack.Shots)
+	aim := math.Atan2(enm.shotY, enm.shotX)
+
+	for i := 0; i < shots; i++ {
+		rotation := aim + (float64(i)-float64(shots-1)/2)*enm.Attack.Spread
+		w.Projectiles = append(w.Projectiles, &Projectile{
+			X:         enm.X,
+			Y:         enm.Y,
+			VelocityX: math.Cos(rotation) * enm.Attack.Speed,
+			VelocityY: math.Sin(rotation) * enm.Attack.Speed,
+			Rotation:  rotation,
+			Active:    true,
+			Faction:   FactionEnemy,
+			Damage:    damage,
+			Bounces:   enm.Attack.Bounces,
+		})
+	}
+}
+
+// Aim returns the direction of the shot the enemy is about to fire, ok is false when it is not aiming
+func (enm *Enemy) Aim() (x, y float64, ok bool) {
+	return enm.shotX, enm.shotY, enm.aiming
+}
+
+// Check the enemy shots against the players, there are only a few players so every shot checks them all
+func (w *World) checkEnemyShots() {
+	for _, prj := range w.Projectiles {
+		if prj.Faction != FactionEnemy || !prj.Active {
+			continue
+		}
+
+		shape := prj.Shape()
+		for _, p := range w.Players {
+			if p.Caught || !Overlaps(shape, p.Shape()) {
+				continue
+			}
+
+			// The shot is used up even when the shield or invulnerability keep the player from harm
+			prj.removed = true
+			if !p.Active(PowerUpShield) {
+				p.hurt(w, prj.Damage, prj.X-prj.VelocityX, prj.Y-prj.VelocityY, shotKnockback)
+			}
+			break
+		}
+	}
+
+	w.Projectiles = slices.DeleteFunc(w.Projectiles, func(prj *Projectile) bool { return prj.removed })
+}
+
+func (a Attack) validate() []error {
+	var errs []error
+	if a.Interval <= 0 {
+		errs = append(errs, fmt.Errorf("attack interval must be positive, got %v", a.Interval))
+	}
+	if a.Windup < 0 {
+		errs = append(errs, fmt.Errorf("attack windup must not be negative, got %v", a.Windup))
+	}
+	if a.Range <= 0 {
+		errs = append(errs, fmt.Errorf("attack range must be positive, got %v", a.Range))
+	}
+	if a.Speed <= 0 {
+		errs = append(errs, fmt.Errorf("attack speed must be positive, got %v", a.Speed))
+	}
+	if a.Damage < 0 {
+		errs = append(errs, fmt.Errorf("attack damage must not be negative, got %v", a.Damage))
+	}
+	if a.Shots < 0 || a.Spread < 0 {
+		errs = append(errs, fmt.Errorf("attack shots and spread must not be negative, got %v and %v", a.Shots, a.Spread))
+	}
+	if a.Bounces < 0 {
+		errs = append(errs, fmt.Errorf("attack bounces must not be negative, got %v", a.Bounces))
+	}
+	return errs
+}
@@ -0,0 +1,128 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Behaviour decides how an enemy moves towards the player
+type Behaviour string
+
+const (
+	BehaviourChase  Behaviour = "chase"  // Steer straight for the player
+	BehaviourZigZag Behaviour = "zigzag" // Chase while swaying from side to side
+	BehaviourOrbit  Behaviour = "orbit"  // Circle around the player, coming closer and closer
+	BehaviourDash   Behaviour = "dash"   // Stop when close, wind up and charge in a straight line
+	BehaviourRanged Behaviour = "ranged" // Keep away from the player at a distance and move around sideways
+)
+
+// Behaviours is the list of all behaviours
+var Behaviours = []Behaviour{BehaviourChase, BehaviourZigZag, BehaviourOrbit, BehaviourDash, BehaviourRanged}
+
+// Phases of the dash behaviour
+const (
+	dashApproach = iota
+	dashWindingUp
+	dashDashing
+	dashRecovering
+)
+
+// behave returns the direction the enemy wants to move in (at most 1 long) and the part of its speed to use
+func (enm *Enemy) behave(w *World, dx, dy, distance, scale float64) (dirX, dirY, speedFactor float64) {
+	enm.ticks += scale
+
+	switch enm.Behaviour {
+	case BehaviourZigZag:
+		// Turn the steered direction back and forth
+		dirX, dirY, speedFactor = enm.steer(w, dx, dy, distance)
+		sin, cos := math.Sincos(zigzagAngle * math.Sin(enm.ticks*2*math.Pi/zigzagPeriod))
+		return dirX*cos - dirY*sin, dirX*sin + dirY*cos, speedFactor
+
+	case BehaviourOrbit:
+		// Head for the orbit which shrinks over time, then go around along it
+		radius := math.Max(0, orbitRadius-enm.ticks*orbitShrink)
+		return enm.keepDistance(w, dx, dy, distance, radius)
+
+	case BehaviourRanged:
+		return enm.keepDistance(w, dx, dy, distance, rangedRadius)
+
+	case BehaviourDash:
+		return enm.dash(w, dx, dy, distance, scale)
+	}
+
+	return enm.steer(w, dx, dy, distance)
+}
+
+// Move towards a circle of the given radius around the player and along it, on the side of the flank
+func (enm *Enemy) keepDistance(w *World, dx, dy, distance, radius float64) (dirX, dirY, speedFactor float64) {
+	seekX, seekY := dx/distance, dy/distance
+
+	// Positive when outside of the circle, negative inside
+	radial := math.Max(-1, math.Min(1, (distance-radius)/spriteSize))
+	dirX = seekX*radial - seekY*enm.flankSide
+	dirY = seekY*radial + seekX*enm.flankSide
+
+	sepX, sepY := enm.separation(w)
+	dirX += sepX * enm.Steering.Separation
+	dirY += sepY * enm.Steering.Separation
+
+	length := math.Hypot(dirX, dirY)
+	if length == 0 {
+		return 0, 0, 0
+	}
+	return dirX / length, dirY / length, 1
+}
+
+// The dash goes through its phases, counting the ticks spent in each of them
+func (enm *Enemy) dash(w *World, dx, dy, distance, scale float64) (dirX, dirY, speedFactor float64) {
+	enm.phaseTicks += scale
+
+	switch enm.phase {
+	case dashWindingUp:
+		// Aim at the player until the very end of the wind up
+		enm.aimX, enm.aimY = dx/distance, dy/distance
+		if enm.phaseTicks >= dashWindup {
+			enm.setPhase(dashDashing)
+		}
+		return 0, 0, 0
+
+	case dashDashing:
+		if enm.phaseTicks >= dashTicks {
+			enm.setPhase(dashRecovering)
+		}
+		return enm.aimX, enm.aimY, dashSpeedup
+
+	case dashRecovering:
+		if enm.phaseTicks >= dashRecovery {
+			enm.setPhase(dashApproach)
+		}
+		return 0, 0, 0
+	}
+
+	if distance < dashRange {
+		enm.setPhase(dashWindingUp)
+	}
+	return enm.steer(w, dx, dy, distance)
+}
+
+func (enm *Enemy) setPhase(phase int) {
+	enm.phase = phase
+	enm.phaseTicks = 0
+}
+
+// WindingUp reports whether the enemy is about to dash, so that it can be shown to the player
+func (enm *Enemy) WindingUp() bool {
+	return enm.Behaviour == BehaviourDash && enm.phase == dashWindingUp
+}
+
+func (b Behaviour) validate() error {
+	if b == "" {
+		return nil
+	}
+	for _, known := range Behaviours {
+		if b == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown behaviour %q", b)
+}
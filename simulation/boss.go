@@ -0,0 +1,207 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Boss is an optional enemy appearing once the last stage starts. It is made of an enemy type from the
+// registry and goes through phases as it loses health. When a StageSet has a boss, the round is won by
+// destroying it, whatever the win condition says.
+type Boss struct {
+	Type   string      `json:"type"`   // Name of the enemy type the boss is made of
+	Scale  float64     `json:"scale"`  // Times the boss is drawn bigger than its sprite, 0 counts as 1
+	Phases []BossPhase `json:"phases"` // Ordered by the health at which they start, from the highest
+}
+
+// BossPhase changes what the boss does, values left out are taken from its enemy type
+type BossPhase struct {
+	Health         float64   `json:"health"` // Part of the health (0 to 1) at which the phase starts
+	Behaviour      Behaviour `json:"behaviour,omitempty"`
+	Speed          float64   `json:"speed,omitempty"` // Pixels per tick
+	Attack         *Attack   `json:"attack,omitempty"`
+	Summon         string    `json:"summon,omitempty"`          // Enemy type of the minions called in
+	Summons        int       `json:"summons,omitempty"`         // Minions called in at once
+	SummonInterval float64   `json:"summon_interval,omitempty"` // Seconds between calling in minions
+}
+
+// State of a boss kept in its Enemy
+type bossState struct {
+	def         Boss
+	phase       int
+	summonTicks float64
+}
+
+func DefaultBoss() *Boss {
+	return &Boss{
+		Type:  "warlord",
+		Scale: 2.5,
+		Phases: []BossPhase{
+			{
+				Health:         1,
+				Attack:         &Attack{Interval: 2.5, Windup: 1, Range: 12 * spriteSize, Speed: 4, Damage: 1, Shots: 3, Spread: 0.3},
+				Summon:         "orc",
+				Summons:        2,
+				SummonInterval: 8,
+			},
+			{
+				Health:         0.6,
+				Behaviour:      BehaviourDash,
+				Speed:          0.9,
+				Attack:         &Attack{Interval: 2, Windup: 0.75, Range: 12 * spriteSize, Speed: 4.5, Damage: 1, Shots: 5, Spread: 0.25},
+				Summon:         "goblin",
+				Summons:        2,
+				SummonInterval: 6,
+			},
+			{
+				Health:         0.3,
+				Behaviour:      BehaviourOrbit,
+				Speed:          1.3,
+				Attack:         &Attack{Interval: 1.5, Windup: 0.5, Range: 12 * spriteSize, Speed: 5, Damage: 1, Shots: 7, Spread: 0.2, Bounces: 1},
+				Summon:         "small_slime",
+				Summons:        4,
+				SummonInterval: 5,
+			},
+		},
+	}
+}
+
+func (b Boss) validate(types map[string]EnemyType) []error {
+	var errs []error
+
+	if _, ok := types[b.Type]; !ok {
+		errs = append(errs, fmt.Errorf("boss: unknown enemy type %q", b.Type))
+	}
+	if b.Scale < 0 {
+		errs = append(errs, fmt.Errorf("boss: scale must not be negative, got %v", b.Scale))
+	}
+	if len(b.Phases) == 0 {
+		errs = append(errs, errors.New("boss: at least one phase is required"))
+	}
+
+	for i, phase := range b.Phases {
+		if i == 0 && phase.Health != 1 {
+			errs = append(errs, fmt.Errorf("boss phase 1: health must be 1, got %v", phase.Health))
+		}
+		if i > 0 && (phase.Health <= 0 || phase.Health >= b.Phases[i-1].Health) {
+			errs = append(errs, fmt.Errorf("boss phase %d: health must be above 0 and below the one of the phase before, got %v", i+1, phase.Health))
+		}
+		if err := phase.Behaviour.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("boss phase %d: %w", i+1, err))
+		}
+		if phase.Speed < 0 {
+			errs = append(errs, fmt.Errorf("boss phase %d: speed must not be negative, got %v", i+1, phase.Speed))
+		}
+		if phase.Attack != nil {
+			for _, err := range phase.Attack.validate() {
+				errs = append(errs, fmt.Errorf("boss phase %d: %w", i+1, err))
+			}
+		}
+		if phase.Summon != "" {
+			if _, ok := types[phase.Summon]; !ok {
+				errs = append(errs, fmt.Errorf("boss phase %d: unknown enemy type %q to summon", i+1, phase.Summon))
+			}
+			if phase.Summons <= 0 || phase.SummonInterval <= 0 {
+				errs = append(errs, fmt.Errorf("boss phase %d: summons and summon_interval must be positive", i+1))
+			}
+		}
+	}
+
+	return errs
+}
+
+// Bring the boss in from the top of the screen
+func (w *World) spawnBoss() {
+	def := *w.Config.Stages.Boss
+
+	enm := w.newEnemy(def.Type, ScreenWidth/2, -spriteSize*def.scale())
+	enm.Scale = def.scale()
+	enm.boss = &bossState{def: def}
+	enm.enterPhase(w, 0)
+
+	w.boss = enm
+	w.Enemies = append(w.Enemies, enm)
+	w.emit(EventBoss)
+}
+
+func (b Boss) scale() float64 {
+	if b.Scale == 0 {
+		return 1
+	}
+	return b.Scale
+}
+
+// Move on to the phase of the current health and call in minions when it is time
+func (enm *Enemy) updateBoss(w *World, scale float64) {
+	b := enm.boss
+
+	health := float64(enm.Health) / float64(enm.MaxHealth)
+	for next := b.phase + 1; next < len(b.def.Phases) && health <= b.def.Phases[next].Health; next++ {
+		enm.enterPhase(w, next)
+	}
+
+	phase := b.def.Phases[b.phase]
+	if phase.Summon == "" {
+		return
+	}
+	b.summonTicks += scale
+	if b.summonTicks >= phase.SummonInterval*TickRate {
+		b.summonTicks = 0
+		w.spawnAround(phase.Summon, phase.Summons, enm.X, enm.Y, enm.Hitbox.bound()+spriteSize)
+	}
+}
+
+func (enm *Enemy) enterPhase(w *World, index int) {
+	b := enm.boss
+	b.phase = index
+	b.summonTicks = 0
+	phase := b.def.Phases[index]
+	t := w.Config.Stages.Types[enm.Type]
+
+	enm.Behaviour = phase.Behaviour
+	if enm.Behaviour == "" {
+		enm.Behaviour = t.Behaviour
+	}
+	if enm.Behaviour == "" {
+		enm.Behaviour = BehaviourChase
+	}
+
+	enm.Speed = phase.Speed
+	if enm.Speed == 0 {
+		enm.Speed = t.Speed
+	}
+	if enm.Speed == 0 {
+		enm.Speed = w.Config.MaxEnemySpeed
+	}
+
+	enm.Attack = phase.Attack
+	if enm.Attack == nil {
+		enm.Attack = t.Attack
+	}
+
+	// Start the new behaviour and attack from the beginning
+	enm.ticks, enm.phase, enm.phaseTicks = 0, 0, 0
+	enm.attackTicks, enm.aiming = 0, false
+}
+
+// IsBoss reports whether the enemy is the boss of the round
+func (enm *Enemy) IsBoss() bool {
+	return enm.boss != nil
+}
+
+// Boss returns the boss while it is alive, nil before it appears and once it is destroyed
+func (w *World) Boss() *Enemy {
+	if w.boss == nil || w.boss.destroyed {
+		return nil
+	}
+	return w.boss
+}
+
+// Put enemies of the named type on a circle around the given point
+func (w *World) spawnAround(name string, count int, x, y, distance float64) {
+	for i := 0; i < count; i++ {
+		angle := w.rng.Float64() * 2 * math.Pi
+		w.Enemies = append(w.Enemies, w.newEnemy(name, x+math.Cos(angle)*distance, y+math.Sin(angle)*distance))
+	}
+}
@@ -0,0 +1,19 @@
+package simulation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"shooter/simulation"
+)
+
+// The stage file spells out values the defaults leave to the config, only the boss is the same in both
+func TestStagesFileBossMatchesDefault(t *testing.T) {
+	stages, err := simulation.LoadStages("../stages.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := simulation.DefaultBoss(); !reflect.DeepEqual(stages.Boss, want) {
+		t.Errorf("boss of stages.json differs from DefaultBoss:\n got %+v\nwant %+v", stages.Boss, want)
+	}
+}
@@ -0,0 +1,201 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Shapes used for collisions. Every position in the simulation addresses the CENTER of a thing,
+// so shapes are placed by their center too and a hit is equally likely from every direction.
+type Shape interface {
+	// bound is the radius of a circle around the center which contains the whole shape
+	bound() float64
+}
+
+type Circle struct {
+	X, Y   float64
+	Radius float64
+}
+
+// Box is an axis-aligned box
+type Box struct {
+	X, Y                  float64
+	HalfWidth, HalfHeight float64
+}
+
+// OrientedBox is a box rotated by Rotation radians around its center, its width lies along the rotation
+type OrientedBox struct {
+	X, Y                  float64
+	HalfWidth, HalfHeight float64
+	Rotation              float64
+}
+
+func (c Circle) bound() float64      { return c.Radius }
+func (b Box) bound() float64         { return math.Hypot(b.HalfWidth, b.HalfHeight) }
+func (o OrientedBox) bound() float64 { return math.Hypot(o.HalfWidth, o.HalfHeight) }
+
+// Overlaps reports whether two shapes touch each other
+func Overlaps(a, b Shape) bool {
+	// Every pair is handled with a circle on the left or both shapes as oriented boxes
+	switch a := a.(type) {
+	case Circle:
+		switch b := b.(type) {
+		case Circle:
+			return math.Hypot(a.X-b.X, a.Y-b.Y) < a.Radius+b.Radius
+		case Box:
+			return circleBox(a, b.oriented())
+		case OrientedBox:
+			return circleBox(a, b)
+		}
+	case Box:
+		if c, ok := b.(Circle); ok {
+			return circleBox(c, a.oriented())
+		}
+		if b, ok := b.(Box); ok {
+			return math.Abs(a.X-b.X) < a.HalfWidth+b.HalfWidth && math.Abs(a.Y-b.Y) < a.HalfHeight+b.HalfHeight
+		}
+		return boxes(a.oriented(), orient(b))
+	case OrientedBox:
+		if c, ok := b.(Circle); ok {
+			return circleBox(c, a)
+		}
+		return boxes(a, orient(b))
+	}
+	return false
+}
+
+func (b Box) oriented() OrientedBox {
+	return OrientedBox{X: b.X, Y: b.Y, HalfWidth: b.HalfWidth, HalfHeight: b.HalfHeight}
+}
+
+// Turn a box of any kind into an oriented one, circles never get here
+func orient(s Shape) OrientedBox {
+	if b, ok := s.(Box); ok {
+		return b.oriented()
+	}
+	return s.(OrientedBox)
+}
+
+// Move the circle into the space of the box, there the closest point of the box is just a clamp
+func circleBox(c Circle, o OrientedBox) bool {
+	sin, cos := math.Sincos(-o.Rotation)
+	dx, dy := c.X-o.X, c.Y-o.Y
+	localX := dx*cos - dy*sin
+	localY := dx*sin + dy*cos
+
+	closestX := math.Max(-o.HalfWidth, math.Min(o.HalfWidth, localX))
+	closestY := math.Max(-o.HalfHeight, math.Min(o.HalfHeight, localY))
+
+	return math.Hypot(localX-closestX, localY-closestY) < c.Radius
+}
+
+// Separating axis test, two boxes overlap unless there is a gap along one of their 4 axes
+func boxes(a, b OrientedBox) bool {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	for _, angle := range [4]float64{a.Rotation, a.Rotation + math.Pi/2, b.Rotation, b.Rotation + math.Pi/2} {
+		axisY, axisX := math.Sincos(angle)
+		distance := math.Abs(dx*axisX + dy*axisY)
+		if distance >= a.projected(axisX, axisY)+b.projected(axisX, axisY) {
+			return false
+		}
+	}
+	return true
+}
+
+// Half of the length of the box when projected onto the axis
+func (o OrientedBox) projected(axisX, axisY float64) float64 {
+	sin, cos := math.Sincos(o.Rotation)
+	return o.HalfWidth*math.Abs(cos*axisX+sin*axisY) + o.HalfHeight*math.Abs(-sin*axisX+cos*axisY)
+}
+
+// Kinds of Hitbox
+const (
+	HitboxCircle   = "circle"
+	HitboxBox      = "box"      // Stays axis-aligned whatever the rotation of its owner
+	HitboxOriented = "oriented" // Turns with its owner
+)
+
+// Hitbox is the definition of the shape something collides with, placed on its center when checked
+type Hitbox struct {
+	Shape  string  `json:"shape"`
+	Radius float64 `json:"radius,omitempty"` // For circles
+	Width  float64 `json:"width,omitempty"`  // For boxes, along the rotation of oriented ones
+	Height float64 `json:"height,omitempty"`
+}
+
+// Hitboxes of everything but the enemies, whose hitbox is part of their stage definition
+var (
+	playerHitbox     = Hitbox{Shape: HitboxCircle, Radius: 0.35 * spriteSize}
+	projectileHitbox = Hitbox{Shape: HitboxOriented, Width: 0.75 * spriteSize, Height: 0.2 * spriteSize}
+	pickupHitbox     = Hitbox{Shape: HitboxCircle, Radius: 0.3 * spriteSize}
+)
+
+func DefaultEnemyHitbox() Hitbox {
+	return Hitbox{Shape: HitboxCircle, Radius: 0.45 * spriteSize}
+}
+
+// At places the hitbox on the given center
+func (h Hitbox) At(x, y, rotation float64) Shape {
+	switch h.Shape {
+	case HitboxBox:
+		return Box{X: x, Y: y, HalfWidth: h.Width / 2, HalfHeight: h.Height / 2}
+	case HitboxOriented:
+		return OrientedBox{X: x, Y: y, HalfWidth: h.Width / 2, HalfHeight: h.Height / 2, Rotation: rotation}
+	default:
+		return Circle{X: x, Y: y, Radius: h.Radius}
+	}
+}
+
+// Grown returns the hitbox made bigger by margin on every side
+func (h Hitbox) Grown(margin float64) Hitbox {
+	h.Radius += margin
+	if h.Shape != HitboxCircle {
+		h.Width += 2 * margin
+		h.Height += 2 * margin
+	}
+	return h
+}
+
+// The radius around the center which contains the whole hitbox in any rotation
+func (h Hitbox) bound() float64 {
+	return h.At(0, 0, 0).bound()
+}
+
+func (h Hitbox) Validate() error {
+	switch h.Shape {
+	case HitboxCircle:
+		if h.Radius <= 0 {
+			return fmt.Errorf("hitbox radius must be positive, got %v", h.Radius)
+		}
+	case HitboxBox, HitboxOriented:
+		if h.Width <= 0 || h.Height <= 0 {
+			return fmt.Errorf("hitbox width and height must be positive, got %v and %v", h.Width, h.Height)
+		}
+	default:
+		return fmt.Errorf("unknown hitbox shape %q, expected %q, %q or %q", h.Shape, HitboxCircle, HitboxBox, HitboxOriented)
+	}
+	return nil
+}
+
+// Hitboxes of the things in the world at their current position, exported to draw them while debugging
+
+func (p *Player) Shape() Shape {
+	return playerHitbox.At(p.X, p.Y, p.Rotation)
+}
+
+// The area in which the player collects things lying on the ground
+func (p *Player) pickupArea() Shape {
+	return playerHitbox.Grown(pickupReach).At(p.X, p.Y, p.Rotation)
+}
+
+func (enm *Enemy) Shape() Shape {
+	return enm.Hitbox.At(enm.X, enm.Y, enm.Heading)
+}
+
+func (prj *Projectile) Shape() Shape {
+	return projectileHitbox.At(prj.X, prj.Y, prj.Rotation)
+}
+
+func (pck *Pickup) Shape() Shape {
+	return pickupHitbox.At(pck.X, pck.Y, 0)
+}
@@ -0,0 +1,147 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"circles overlapping", Circle{0, 0, 5}, Circle{8, 0, 5}, true},
+		{"circles touching", Circle{0, 0, 5}, Circle{10, 0, 5}, false},
+		{"circles apart", Circle{0, 0, 5}, Circle{0, 11, 5}, false},
+
+		{"circle and box overlapping", Circle{0, 0, 5}, Box{9, 0, 5, 5}, true},
+		{"circle and box touching", Circle{0, 0, 5}, Box{10, 0, 5, 5}, false},
+		{"circle off the corner of a box", Circle{0, 0, 5}, Box{8, 8, 4, 4}, false},
+		{"circle inside a box", Circle{1, 1, 2}, Box{0, 0, 10, 10}, true},
+
+		{"circle and oriented box overlapping", Circle{0, 0, 5}, OrientedBox{10, 0, 6, 1, 0}, true},
+		{"circle and oriented box turned away", Circle{0, 0, 5}, OrientedBox{10, 0, 6, 1, math.Pi / 2}, false},
+		{"circle and oriented box turned towards", Circle{0, 0, 5}, OrientedBox{0, 10, 6, 1, math.Pi / 2}, true},
+
+		{"boxes overlapping", Box{0, 0, 5, 5}, Box{9, 0, 5, 5}, true},
+		{"boxes touching", Box{0, 0, 5, 5}, Box{10, 0, 5, 5}, false},
+		{"boxes apart", Box{0, 0, 5, 5}, Box{0, 11, 5, 5}, false},
+
+		{"box and oriented box overlapping", Box{0, 0, 5, 5}, OrientedBox{12, 0, 8, 1, 0}, true},
+		{"box and oriented box turned away", Box{0, 0, 5, 5}, OrientedBox{12, 0, 8, 1, math.Pi / 2}, false},
+		// The bounds overlap, but the diamond stays clear of the corner of the box
+		{"box and diamond by the corner", Box{0, 0, 5, 5}, OrientedBox{9, 9, 3, 3, math.Pi / 4}, false},
+		{"box and diamond over the corner", Box{0, 0, 5, 5}, OrientedBox{7, 7, 3, 3, math.Pi / 4}, true},
+
+		{"oriented boxes crossing", OrientedBox{0, 0, 10, 1, 0}, OrientedBox{0, 0, 10, 1, math.Pi / 2}, true},
+		{"oriented boxes side by side", OrientedBox{0, 0, 10, 1, math.Pi / 4}, OrientedBox{-1.5 * math.Sqrt2, 1.5 * math.Sqrt2, 10, 1, math.Pi / 4}, false},
+		{"oriented boxes rubbing", OrientedBox{0, 0, 10, 1, math.Pi / 4}, OrientedBox{-0.75 * math.Sqrt2, 0.75 * math.Sqrt2, 10, 1, math.Pi / 4}, true},
+		{"oriented box ends apart", OrientedBox{0, 0, 5, 1, math.Pi / 4}, OrientedBox{8, 8, 5, 1, math.Pi / 4}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The order of the shapes never matters
+			if got := Overlaps(tt.a, tt.b); got != tt.want {
+				t.Errorf("Overlaps(%+v, %+v) = %t, want %t", tt.a, tt.b, got, tt.want)
+			}
+			if got := Overlaps(tt.b, tt.a); got != tt.want {
+				t.Errorf("Overlaps(%+v, %+v) = %t, want %t", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+// An oriented box which is not turned is the same as an axis-aligned one
+func TestOverlapsUnturnedOrientedBox(t *testing.T) {
+	others := []Shape{Circle{6, 2, 2}, Box{-7, 3, 3, 1}, OrientedBox{0, 8, 4, 2, 0.3}}
+	box := Box{0, 0, 5, 4}
+	oriented := OrientedBox{0, 0, 5, 4, 0}
+
+	for _, other := range others {
+		if Overlaps(box, other) != Overlaps(oriented, other) {
+			t.Errorf("%+v and %+v disagree about %+v", box, oriented, other)
+		}
+	}
+}
+
+func TestHitboxAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		hitbox Hitbox
+		want   Shape
+	}{
+		{"circle", Hitbox{Shape: HitboxCircle, Radius: 3}, Circle{1, 2, 3}},
+		{"box ignores the rotation", Hitbox{Shape: HitboxBox, Width: 4, Height: 6}, Box{1, 2, 2, 3}},
+		{"oriented box turns", Hitbox{Shape: HitboxOriented, Width: 4, Height: 6}, OrientedBox{1, 2, 2, 3, 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hitbox.At(1, 2, 0.5); got != tt.want {
+				t.Errorf("At(1, 2, 0.5) = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// An oriented hitbox of an enemy lies along the direction it moves in, here straight towards the player
+func TestEnemyHitboxTurnsWithHeading(t *testing.T) {
+	tests := []struct {
+		name    string
+		dx, dy  float64 // Start of the enemy from the player
+		heading float64
+	}{
+		{"from the left", -200, 0, 0},
+		{"from above", 0, -200, math.Pi / 2},
+		{"from the right", 200, 0, math.Pi},
+		{"from below left", -150, 150, -math.Pi / 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorld(DefaultConfig(), 1)
+			p := w.Players[0]
+			enm := w.newEnemy("slime", p.X+tt.dx, p.Y+tt.dy)
+			enm.Hitbox = Hitbox{Shape: HitboxOriented, Width: 30, Height: 10}
+			w.Enemies = []*Enemy{enm}
+			w.fillEnemyGrid()
+
+			enm.Update(w, 1)
+			box, ok := enm.Shape().(OrientedBox)
+			if !ok {
+				t.Fatalf("Shape() = %T, want OrientedBox", enm.Shape())
+			}
+			if diff := math.Remainder(box.Rotation-tt.heading, 2*math.Pi); math.Abs(diff) > 1e-9 {
+				t.Errorf("rotation %v, want %v", box.Rotation, tt.heading)
+			}
+		})
+	}
+}
+
+func TestHitboxValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hitbox  Hitbox
+		wantErr bool
+	}{
+		{"circle", Hitbox{Shape: HitboxCircle, Radius: 3}, false},
+		{"box", Hitbox{Shape: HitboxBox, Width: 4, Height: 6}, false},
+		{"oriented box", Hitbox{Shape: HitboxOriented, Width: 4, Height: 6}, false},
+		{"circle without a radius", Hitbox{Shape: HitboxCircle}, true},
+		{"circle with a negative radius", Hitbox{Shape: HitboxCircle, Radius: -1}, true},
+		{"box without a height", Hitbox{Shape: HitboxBox, Width: 4}, true},
+		{"oriented box with a negative width", Hitbox{Shape: HitboxOriented, Width: -4, Height: 6}, true},
+		{"unknown shape", Hitbox{Shape: "triangle", Radius: 3}, true},
+		{"no shape", Hitbox{Radius: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hitbox.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
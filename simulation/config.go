@@ -0,0 +1,135 @@
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Config holds the balance values of a round, every World keeps its own copy
+type Config struct {
+	PlayerSpeed       float64 `json:"player_speed"`        // Pixels per tick
+	InitialBoltAmount int     `json:"initial_bolt_amount"` // Also the most bolts a player can carry
+	ProjectileSpeed   float64 `json:"projectile_speed"`    // Pixels per tick
+	StageDuration     float64 `json:"stage_duration"`      // Seconds
+	SpawnInterval     float64 `json:"spawn_interval"`      // Seconds
+	MaxEnemySpeed     float64 `json:"max_enemy_speed"`     // Pixels per tick
+	Players           int     `json:"players"`             // Players playing together on one screen
+
+	PlayerHealth    int     `json:"player_health"`   // Hit points every player starts with
+	Invulnerability float64 `json:"invulnerability"` // Seconds a player cannot be hurt again after a hit
+	OneHit          bool    `json:"one_hit"`         // Any hit catches the player whatever the health, as in the original game
+
+	Weapon  WeaponKind `json:"weapon"`  // Weapon every player starts with
+	Weapons WeaponSet  `json:"weapons"` // Stats of every weapon, a weapon in the file only needs the values it changes
+
+	PowerUps       map[PowerUp]float64 `json:"power_ups"`       // Seconds each power up lasts, 0 for instant or permanent ones
+	PickupLifetime float64             `json:"pickup_lifetime"` // Seconds a pickup lies on the ground before it vanishes
+	PickupInterval float64             `json:"pickup_interval"` // Seconds between pickups appearing on their own, 0 for never
+
+	Stages StageSet `json:"-"` // Loaded from its own file, see LoadStages
+}
+
+// DefaultConfig returns the balance the game was designed with
+func DefaultConfig() Config {
+	return Config{
+		PlayerSpeed:       2,
+		InitialBoltAmount: 10,
+		ProjectileSpeed:   10,
+		StageDuration:     10,
+		SpawnInterval:     1,
+		MaxEnemySpeed:     0.75,
+		Players:           1,
+		PlayerHealth:      5,
+		Invulnerability:   1,
+		Weapon:            WeaponCrossbow,
+		Weapons:           DefaultWeapons(),
+		PowerUps: map[PowerUp]float64{
+			PowerUpMultishot: 10,
+			PowerUpPiercing:  10,
+			PowerUpSpeed:     8,
+			PowerUpShield:    5,
+			PowerUpRefill:    0,
+			PowerUpCapacity:  0,
+		},
+		PickupLifetime: 10,
+		PickupInterval: 20,
+		Stages:         DefaultStages(),
+	}
+}
+
+// LoadConfig reads a JSON config file. Keys missing from the file keep their default values,
+// unknown keys and values out of range are reported as errors.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return cfg, fmt.Errorf("config: %w", err)
+	}
+	defer file.Close()
+
+	if err := decodeStrict(file, &cfg); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that every value is in a range the game can be played with
+func (cfg Config) Validate() error {
+	var errs []error
+
+	check := func(key string, value, min, max float64) {
+		if value < min || value > max {
+			errs = append(errs, fmt.Errorf("%s must be between %v and %v, got %v", key, min, max, value))
+		}
+	}
+
+	check("player_speed", cfg.PlayerSpeed, 0.1, 50)
+	check("initial_bolt_amount", float64(cfg.InitialBoltAmount), 0, 1000)
+	check("projectile_speed", cfg.ProjectileSpeed, 0.1, 100)
+	check("stage_duration", cfg.StageDuration, 1, 3600)
+	check("spawn_interval", cfg.SpawnInterval, 0.01, 3600)
+	check("max_enemy_speed", cfg.MaxEnemySpeed, 0, 50)
+	check("players", float64(cfg.Players), 1, MaxPlayers)
+	check("player_health", float64(cfg.PlayerHealth), 1, 1000)
+	check("invulnerability", cfg.Invulnerability, 0, 60)
+	check("pickup_lifetime", cfg.PickupLifetime, 1, 3600)
+	check("pickup_interval", cfg.PickupInterval, 0, 3600)
+
+	if err := validateWeapon(cfg.Weapon); err != nil {
+		errs = append(errs, fmt.Errorf("weapon: %w", err))
+	}
+	errs = append(errs, cfg.Weapons.validate()...)
+
+	for pu, duration := range cfg.PowerUps {
+		if err := validatePowerUp(pu); err != nil {
+			errs = append(errs, fmt.Errorf("power_ups: %w", err))
+			continue
+		}
+		check("power_ups."+string(pu), duration, 0, 600)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Decode a single JSON value into v, refusing unknown keys and anything after the value
+func decodeStrict(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after the top-level value")
+	}
+
+	return nil
+}
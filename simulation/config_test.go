@@ -0,0 +1,39 @@
+package simulation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"shooter/simulation"
+)
+
+// The config file shipped with the game spells out the defaults, so that it is a starting point to edit.
+// Only its crossbow differs, its bolts bounce and slide to a stop.
+func TestConfigFileMatchesDefaults(t *testing.T) {
+	cfg, err := simulation.LoadConfig("../config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := simulation.DefaultConfig()
+	crossbow := want.Weapons[simulation.WeaponCrossbow]
+	crossbow.Bounces, crossbow.Friction = 1, 0.01
+	want.Weapons[simulation.WeaponCrossbow] = crossbow
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config.json differs from DefaultConfig:\n got %+v\nwant %+v", cfg, want)
+	}
+}
+
+func TestWeaponsOverride(t *testing.T) {
+	weapons := simulation.DefaultWeapons()
+	if err := weapons.UnmarshalJSON([]byte(`{"bow": {"damage": 3}}`)); err != nil {
+		t.Fatal(err)
+	}
+	bow := weapons[simulation.WeaponBow]
+	if bow.Damage != 3 || bow.Magazine != 4 || bow.Kind != simulation.WeaponBow {
+		t.Errorf("bow = %+v, want damage 3 over the default stats", bow)
+	}
+	if err := weapons.UnmarshalJSON([]byte(`{"bow": {"sharpness": 3}}`)); err == nil {
+		t.Error("unknown key was accepted")
+	}
+}
@@ -0,0 +1,100 @@
+package simulation
+
+import (
+	"math"
+)
+
+type Enemy struct {
+	Type      string // Name of its EnemyType
+	Sprite    string // Name in the sprite atlas
+	Behaviour Behaviour
+	Health    int
+	MaxHealth int
+	Scale     float64 // Times the enemy is drawn bigger than its sprite
+	Reach     float64
+	Speed     float64
+	Damage    int
+	Knockback float64
+	Score     int
+	Steering  Steering
+	Hitbox    Hitbox
+	Attack    *Attack // Nil for enemies which do not shoot
+	Drops     []Drop
+	X, Y      float64 // These address the CENTER of an image
+	VelocityX float64 // Pixels moved during the last tick
+	VelocityY float64
+	Heading   float64 // Radians of the direction the enemy last moved in, oriented hitboxes turn with it
+	flankSide float64 // 1 or -1, the side from which the enemy flanks the player
+	destroyed bool    // Set when destroyed during its own Update, it is removed after all enemies moved
+	boss      *bossState
+
+	// State of the behaviour
+	ticks      float64 // Ticks since the enemy appeared
+	phase      int
+	phaseTicks float64 // Ticks spent in the current phase
+	aimX, aimY float64 // Direction of the dash
+
+	// State of the attack
+	attackTicks  float64 // Ticks since the last shot, or since the aiming started
+	aiming       bool
+	shotX, shotY float64 // Direction of the shot being aimed
+}
+
+func (enm *Enemy) Update(w *World, scale float64) {
+	// Enemies can be destroyed by a swing before their turn
+	if enm.destroyed {
+		return
+	}
+	enm.VelocityX, enm.VelocityY = 0, 0
+
+	// Chase the closest player who is still free
+	p := w.nearestPlayer(enm.X, enm.Y)
+	if p == nil {
+		return
+	}
+
+	// Calculate the difference in position
+	dx := p.X - enm.X
+	dy := p.Y - enm.Y
+
+	// Calculate the distance to the destination point (enemy to player), never 0 as the enemy would catch them first
+	distance := math.Max(math.Sqrt(dx*dx+dy*dy), 1e-9)
+
+	// A boss changes phases and summons even when standing on the player
+	if enm.boss != nil {
+		enm.updateBoss(w, scale)
+	}
+
+	// If the player's hitbox is within reach, the player gets hurt
+	if Overlaps(Circle{X: enm.X, Y: enm.Y, Radius: enm.Reach}, p.Shape()) {
+		// Unless the player is shielded, then the enemy is the one destroyed. A boss is too strong for that.
+		if p.Active(PowerUpShield) {
+			if enm.boss == nil {
+				w.enemyDestroyed(enm)
+			}
+			return
+		}
+
+		p.hurt(w, enm.Damage, enm.X, enm.Y, enm.Knockback)
+		return
+	}
+
+	// Stand still while aiming a shot
+	if enm.Attack != nil && enm.updateAttack(w, dx, dy, distance, scale) {
+		return
+	}
+
+	// Let the behaviour of the enemy decide the direction to move in
+	dirX, dirY, speedFactor := enm.behave(w, dx, dy, distance, scale)
+
+	// Move the image towards the center
+	enm.VelocityX = dirX * enm.Speed * speedFactor * scale
+	enm.VelocityY = dirY * enm.Speed * speedFactor * scale
+	enm.X += enm.VelocityX
+	enm.Y += enm.VelocityY
+
+	// Standing still keeps the heading it had
+	if enm.VelocityX != 0 || enm.VelocityY != 0 {
+		enm.Heading = math.Atan2(enm.VelocityY, enm.VelocityX)
+	}
+}
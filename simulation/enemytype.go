@@ -0,0 +1,278 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// EnemyType is one kind of enemy in the registry of a StageSet, stages refer to it by its name
+type EnemyType struct {
+	Sprite    string    `json:"sprite"`    // Name in the sprite atlas, only used for drawing
+	Behaviour Behaviour `json:"behaviour"` // How the enemy moves, "chase" when left out
+	Speed     float64   `json:"speed"`     // Pixels per tick, 0 uses max_enemy_speed from the Config
+	Health    int       `json:"health"`    // Hits the enemy takes before it is destroyed, 0 counts as 1
+	Reach     float64   `json:"reach"`     // Distance from the center of the enemy at which it catches the player
+	Damage    int       `json:"damage"`    // Health taken from the player caught, 0 counts as 1
+	Knockback float64   `json:"knockback"` // Pixels the player caught is pushed away
+	Score     int       `json:"score"`     // Points for destroying the enemy
+
+	Steering  *Steering `json:"steering,omitempty"`   // Weights of the steering forces, DefaultSteering when left out
+	Hitbox    *Hitbox   `json:"hitbox,omitempty"`     // Shape projectiles hit, DefaultEnemyHitbox when left out
+	Attack    *Attack   `json:"attack,omitempty"`     // Shots fired at the players, none when left out
+	Drops     []Drop    `json:"drops,omitempty"`      // Power ups left behind when destroyed, their chances add up to at most 1
+	SplitInto string    `json:"split_into,omitempty"` // Type of the enemies appearing in its place when destroyed
+	Splits    int       `json:"splits,omitempty"`     // How many of them appear
+}
+
+// DefaultEnemyTypes returns the built-in registry, a stage file can add types to it or replace them
+func DefaultEnemyTypes() map[string]EnemyType {
+	return map[string]EnemyType{
+		"slime": {
+			Sprite:    "monster.big_slime",
+			Health:    2,
+			Reach:     0.45 * spriteSize,
+			Damage:    1,
+			Knockback: spriteSize,
+			Score:     2,
+			Hitbox:    &Hitbox{Shape: HitboxBox, Width: 22, Height: 16}, // Flat, the slime only fills the bottom of its sprite
+			Drops:     []Drop{{PowerUp: PowerUpRefill, Chance: 0.05}},
+			SplitInto: "small_slime",
+			Splits:    2,
+		},
+		"small_slime": {
+			Sprite:    "monster.small_slime",
+			Behaviour: BehaviourZigZag,
+			Speed:     1,
+			Reach:     0.35 * spriteSize,
+			Damage:    1,
+			Knockback: 0.5 * spriteSize,
+			Score:     1,
+			Hitbox:    &Hitbox{Shape: HitboxCircle, Radius: 0.3 * spriteSize},
+		},
+		"orc": {
+			Sprite:    "monster.orc",
+			Health:    2,
+			Reach:     0.65 * spriteSize,
+			Damage:    1,
+			Knockback: 1.5 * spriteSize,
+			Score:     3,
+			Steering:  &Steering{Seek: 1, Separation: 1.5, Flank: 0.8},
+			Drops:     []Drop{{PowerUp: PowerUpMultishot, Chance: 0.05}, {PowerUp: PowerUpSpeed, Chance: 0.05}},
+		},
+		"goblin": {
+			Sprite:    "monster.goblin",
+			Behaviour: BehaviourDash,
+			Reach:     0.45 * spriteSize,
+			Damage:    1,
+			Knockback: spriteSize,
+			Score:     3,
+			Hitbox:    &Hitbox{Shape: HitboxCircle, Radius: 0.35 * spriteSize},
+			Drops:     []Drop{{PowerUp: PowerUpSpeed, Chance: 0.05}, {Weapon: WeaponBow, Chance: 0.05}},
+		},
+		"ogre": {
+			Sprite:    "monster.ettin",
+			Health:    4,
+			Reach:     0.85 * spriteSize,
+			Damage:    2,
+			Knockback: 2 * spriteSize,
+			Score:     5,
+			Steering:  &Steering{Seek: 1, Separation: 2, Arrival: 0.5},
+			Hitbox:    &Hitbox{Shape: HitboxBox, Width: 0.9 * spriteSize, Height: spriteSize},
+			Drops: []Drop{
+				{PowerUp: PowerUpPiercing, Chance: 0.05},
+				{PowerUp: PowerUpShield, Chance: 0.05},
+				{PowerUp: PowerUpCapacity, Chance: 0.03},
+				{Weapon: WeaponSword, Chance: 0.05},
+			},
+		},
+		"warlord": {
+			Sprite:    "monster.two_headed_ettin",
+			Speed:     0.6,
+			Health:    40,
+			Reach:     1.1 * spriteSize,
+			Damage:    2,
+			Knockback: 3 * spriteSize,
+			Score:     50,
+			Steering:  &Steering{Seek: 1, Arrival: 0.5},
+			Hitbox:    &Hitbox{Shape: HitboxBox, Width: 2 * spriteSize, Height: 2.4 * spriteSize},
+		},
+		"shaman": {
+			Sprite:    "monster.faceless_monk",
+			Behaviour: BehaviourRanged,
+			Health:    2,
+			Reach:     0.45 * spriteSize,
+			Damage:    1,
+			Knockback: spriteSize,
+			Score:     4,
+			Attack:    &Attack{Interval: 3, Windup: 0.75, Range: 10 * spriteSize, Speed: 4, Damage: 1},
+			Drops:     []Drop{{PowerUp: PowerUpShield, Chance: 0.05}, {Weapon: WeaponStaff, Chance: 0.05}},
+		},
+		"cultist": {
+			Sprite:    "monster.unholy_cardinal",
+			Behaviour: BehaviourOrbit,
+			Speed:     1.2,
+			Health:    2,
+			Reach:     0.45 * spriteSize,
+			Damage:    1,
+			Knockback: spriteSize,
+			Score:     4,
+			Drops:     []Drop{{PowerUp: PowerUpMultishot, Chance: 0.05}},
+		},
+	}
+}
+
+// Check the values of a single type, names it refers to are checked by the StageSet
+func (t EnemyType) validate() []error {
+	var errs []error
+
+	if t.Sprite == "" {
+		errs = append(errs, errors.New("sprite must be given"))
+	}
+	if err := t.Behaviour.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if t.Speed < 0 {
+		errs = append(errs, fmt.Errorf("speed must not be negative, got %v", t.Speed))
+	}
+	if t.Health < 0 {
+		errs = append(errs, fmt.Errorf("health must not be negative, got %v", t.Health))
+	}
+	if t.Reach <= 0 {
+		errs = append(errs, fmt.Errorf("reach must be positive, got %v", t.Reach))
+	}
+	if t.Damage < 0 {
+		errs = append(errs, fmt.Errorf("damage must not be negative, got %v", t.Damage))
+	}
+	if t.Knockback < 0 {
+		errs = append(errs, fmt.Errorf("knockback must not be negative, got %v", t.Knockback))
+	}
+	if t.Score < 0 {
+		errs = append(errs, fmt.Errorf("score must not be negative, got %v", t.Score))
+	}
+	if t.Splits < 0 {
+		errs = append(errs, fmt.Errorf("splits must not be negative, got %v", t.Splits))
+	}
+
+	totalChance := 0.0
+	for _, drop := range t.Drops {
+		if err := drop.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("drops: %w", err))
+		}
+		if drop.Chance < 0 || drop.Chance > 1 {
+			errs = append(errs, fmt.Errorf("drop chance must be between 0 and 1, got %v", drop.Chance))
+		}
+		totalChance += drop.Chance
+	}
+	if totalChance > 1 {
+		errs = append(errs, fmt.Errorf("drop chances add up to %v, more than 1", totalChance))
+	}
+
+	if t.Hitbox != nil {
+		if err := t.Hitbox.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if t.Attack != nil {
+		errs = append(errs, t.Attack.validate()...)
+	}
+
+	if s := t.Steering; s != nil {
+		if s.Seek < 0 || s.Separation < 0 || s.Flank < 0 {
+			errs = append(errs, fmt.Errorf("steering weights must not be negative"))
+		}
+		if s.Arrival < 0 || s.Arrival > 1 {
+			errs = append(errs, fmt.Errorf("steering arrival must be between 0 and 1, got %v", s.Arrival))
+		}
+	}
+
+	return errs
+}
+
+// Check that every type splits into a known type and splitting ends at some point
+func validateSplits(types map[string]EnemyType) []error {
+	var errs []error
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		seen := map[string]bool{name: true}
+		for current := types[name]; current.SplitInto != "" && current.Splits > 0; {
+			next, ok := types[current.SplitInto]
+			if !ok {
+				errs = append(errs, fmt.Errorf("enemy type %q: splits into unknown type %q", name, current.SplitInto))
+				break
+			}
+			if seen[current.SplitInto] {
+				errs = append(errs, fmt.Errorf("enemy type %q: splitting never ends, it comes back to %q", name, current.SplitInto))
+				break
+			}
+			seen[current.SplitInto] = true
+			current = next
+		}
+	}
+
+	return errs
+}
+
+// Create an enemy of the named type at the given position, with the defaults of the type filled in
+func (w *World) newEnemy(name string, x, y float64) *Enemy {
+	t := w.Config.Stages.Types[name]
+
+	enm := &Enemy{
+		Type:      name,
+		Sprite:    t.Sprite,
+		Behaviour: t.Behaviour,
+		Speed:     t.Speed,
+		Health:    t.Health,
+		Scale:     1,
+		Reach:     t.Reach,
+		Damage:    t.Damage,
+		Knockback: t.Knockback,
+		Score:     t.Score,
+		Steering:  DefaultSteering(),
+		Hitbox:    DefaultEnemyHitbox(),
+		Attack:    t.Attack,
+		Drops:     t.Drops,
+		X:         x,
+		Y:         y,
+		flankSide: 1,
+	}
+
+	if enm.Behaviour == "" {
+		enm.Behaviour = BehaviourChase
+	}
+	if enm.Speed == 0 {
+		enm.Speed = w.Config.MaxEnemySpeed
+	}
+	if enm.Health == 0 {
+		enm.Health = 1
+	}
+	enm.MaxHealth = enm.Health
+	if enm.Damage == 0 {
+		enm.Damage = 1
+	}
+	if t.Steering != nil {
+		enm.Steering = *t.Steering
+	}
+	if t.Hitbox != nil {
+		enm.Hitbox = *t.Hitbox
+	}
+
+	if w.rng.Intn(2) == 0 {
+		enm.flankSide = -1
+	}
+
+	return enm
+}
+
+// Put the enemies the destroyed one splits into around its position
+func (w *World) splitEnemy(enm *Enemy) {
+	t := w.Config.Stages.Types[enm.Type]
+	w.spawnAround(t.SplitInto, t.Splits, enm.X, enm.Y, splitDistance)
+}
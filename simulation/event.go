@@ -0,0 +1,23 @@
+package simulation
+
+// Event is something which happened during the last Step, so that the game can play a sound for it.
+// The simulation itself never reads them.
+type Event string
+
+const (
+	EventShot      Event = "shot"       // A player fired or swung their weapon
+	EventEnemyShot Event = "enemy_shot" // An enemy fired at the players
+	EventHit       Event = "hit"        // An enemy took damage and survived it
+	EventDestroyed Event = "destroyed"  // An enemy was destroyed
+	EventHurt      Event = "hurt"       // A player took damage
+	EventPickup    Event = "pickup"     // A player picked up a bolt, a power up or a weapon
+	EventStage     Event = "stage"      // The next stage began
+	EventBoss      Event = "boss"       // The boss appeared
+	EventGameOver  Event = "game_over"  // All players were caught
+	EventWon       Event = "won"        // The round was won
+)
+
+// Keep the event until the next Step
+func (w *World) emit(e Event) {
+	w.Events = append(w.Events, e)
+}
@@ -0,0 +1,62 @@
+package simulation
+
+import (
+	"math"
+)
+
+// Grid is a uniform spatial grid. Things are put into the cell under their position, so that finding
+// the ones near a point only needs to look at a few cells instead of checking every single one.
+type Grid[T any] struct {
+	cellSize float64
+	cells    map[[2]int][]T
+}
+
+func NewGrid[T any](cellSize float64) *Grid[T] {
+	return &Grid[T]{
+		cellSize: cellSize,
+		cells:    map[[2]int][]T{},
+	}
+}
+
+// Clear empties the grid but keeps the memory of its cells for the next tick
+func (g *Grid[T]) Clear() {
+	for key, cell := range g.cells {
+		g.cells[key] = cell[:0]
+	}
+}
+
+func (g *Grid[T]) Insert(x, y float64, item T) {
+	key := g.cell(x, y)
+	g.cells[key] = append(g.cells[key], item)
+}
+
+// Query calls fn for everything in the cells touching the square around the point. It may include things
+// further away than radius, so fn has to check the exact distance. Items are visited in a fixed order,
+// returning false from fn stops the query.
+func (g *Grid[T]) Query(x, y, radius float64, fn func(T) bool) {
+	minCell := g.cell(x-radius, y-radius)
+	maxCell := g.cell(x+radius, y+radius)
+
+	for cx := minCell[0]; cx <= maxCell[0]; cx++ {
+		for cy := minCell[1]; cy <= maxCell[1]; cy++ {
+			for _, item := range g.cells[[2]int{cx, cy}] {
+				if !fn(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Cells calls fn with the top left corner, the size and the amount of things of every cell which is not empty
+func (g *Grid[T]) Cells(fn func(x, y, size float64, count int)) {
+	for key, cell := range g.cells {
+		if len(cell) > 0 {
+			fn(float64(key[0])*g.cellSize, float64(key[1])*g.cellSize, g.cellSize, len(cell))
+		}
+	}
+}
+
+func (g *Grid[T]) cell(x, y float64) [2]int {
+	return [2]int{int(math.Floor(x / g.cellSize)), int(math.Floor(y / g.cellSize))}
+}
@@ -0,0 +1,50 @@
+package simulation
+
+import (
+	"time"
+)
+
+const (
+	ScreenHeight = 480
+	ScreenWidth  = 640
+
+	// The simulation advances in fixed ticks, speeds below are given in pixels per tick
+	TickRate     = 60
+	TickDuration = time.Second / TickRate
+
+	// Balance values (speeds, bolts, stage timing) are set at runtime, see Config
+
+	spriteSize = 32
+	gridCell   = 2 * spriteSize // Cell size of the grids used to find things close to each other
+
+	pickupReach   = 0.25 * spriteSize // Players collect bolts and power ups which come this close to their hitbox
+	stopSpeed     = 0.5               // Slower than this a sliding projectile comes to a stop, in pixels per tick
+	shotKnockback = 0.25 * spriteSize // Enemy shots push the player this far away
+	minCharge     = 0.25              // Part of the full charge a staff shot always has
+	swingTime     = 0.2               // Seconds a swing is shown for
+
+	multishotBolts   = 3   // Bolts fired at once with multishot, only one of them is taken from the player
+	multishotSpread  = 0.2 // Radians between the bolts of multishot
+	piercingEnemies  = 2   // Enemies a piercing bolt flies through before it stops
+	speedBoost       = 1.5 // Speed multiplier of the speed power up
+	capacityIncrease = 5   // Bolts added to the capacity of the player
+	indicatorTime    = 2.0 // Seconds the HUD shows a power up which has no duration of its own
+
+	separationRadius = spriteSize     // Enemies closer than this push each other away
+	flankRadius      = 6 * spriteSize // Flanking fades out from this distance to the player
+	arrivalRadius    = 3 * spriteSize // Arriving enemies slow down from this distance to the player
+	minArrivalSpeed  = 0.3            // Part of the speed an arriving enemy always keeps
+
+	// Timings of the behaviours are given in ticks, like the speeds
+	zigzagAngle   = 1.0  // Radians the direction sways to each side
+	zigzagPeriod  = 90.0 // Ticks of a full sway there and back
+	orbitRadius   = 5 * spriteSize
+	orbitShrink   = 0.1 // Pixels the orbit gets smaller each tick
+	dashRange     = 4 * spriteSize
+	dashWindup    = 40.0 // Ticks the enemy stands still before the dash, so the player sees it coming
+	dashTicks     = 30.0
+	dashSpeedup   = 5.0 // Speed multiplier while dashing
+	dashRecovery  = 60.0
+	rangedRadius  = 6 * spriteSize // Distance a ranged enemy keeps from the player
+	splitDistance = 0.5 * spriteSize
+)
@@ -0,0 +1,185 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// PowerUp is the effect a Pickup gives to the player who collects it
+type PowerUp string
+
+const (
+	PowerUpMultishot PowerUp = "multishot" // Every shot fires a fan of extra bolts
+	PowerUpPiercing  PowerUp = "piercing"  // Bolts fly through enemies
+	PowerUpSpeed     PowerUp = "speed"     // The player moves faster
+	PowerUpShield    PowerUp = "shield"    // Enemies touching the player are destroyed instead of catching them
+	PowerUpRefill    PowerUp = "refill"    // All bolts are given back at once
+	PowerUpCapacity  PowerUp = "capacity"  // The player can carry more bolts
+)
+
+// PowerUps lists all the power ups in the order they are shown to the player
+var PowerUps = []PowerUp{
+	PowerUpMultishot, PowerUpPiercing, PowerUpSpeed, PowerUpShield, PowerUpRefill, PowerUpCapacity,
+}
+
+// Pickup is a power up or a weapon lying on the ground until a player collects it or it vanishes
+type Pickup struct {
+	PowerUp   PowerUp
+	Weapon    WeaponKind    // Set instead of the PowerUp for weapons
+	X, Y      float64       // These address the CENTER of an image
+	Remaining time.Duration // Time left before the pickup vanishes
+}
+
+// Drop is a power up or a weapon an enemy leaves behind with the given chance when destroyed
+type Drop struct {
+	PowerUp PowerUp    `json:"power_up,omitempty"`
+	Weapon  WeaponKind `json:"weapon,omitempty"` // Instead of the power up
+	Chance  float64    `json:"chance"`           // From 0 to 1
+}
+
+func (pck *Pickup) Update(dt time.Duration) {
+	pck.Remaining -= dt
+}
+
+// Effect is a power up active on a player
+type Effect struct {
+	PowerUp   PowerUp
+	Remaining time.Duration // Time left before the effect ends, or before the HUD stops showing it
+}
+
+// Active reports whether the power up currently has an effect on the player
+func (p *Player) Active(pu PowerUp) bool {
+	for _, eff := range p.Effects {
+		if eff.PowerUp == pu {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply the power up to the player, collecting a power up which is already active restarts its time
+func (p *Player) applyPowerUp(w *World, pu PowerUp) {
+	duration := seconds(w.Config.PowerUps[pu])
+
+	switch pu {
+	case PowerUpRefill:
+		p.BoltAmount = max(p.BoltAmount, p.BoltCapacity)
+	case PowerUpCapacity:
+		p.BoltCapacity += capacityIncrease
+	}
+
+	// Power ups without a duration last forever or happen at once, they are only shown for a moment
+	if duration == 0 {
+		duration = seconds(indicatorTime)
+	}
+
+	for i := range p.Effects {
+		if p.Effects[i].PowerUp == pu {
+			p.Effects[i].Remaining = duration
+			return
+		}
+	}
+	p.Effects = append(p.Effects, Effect{PowerUp: pu, Remaining: duration})
+}
+
+// Count down the effects and remove the ones which ended
+func (p *Player) updateEffects(dt time.Duration) {
+	kept := p.Effects[:0]
+	for _, eff := range p.Effects {
+		eff.Remaining -= dt
+		if eff.Remaining > 0 {
+			kept = append(kept, eff)
+		}
+	}
+	p.Effects = kept
+}
+
+// Logic to maybe leave a pickup where an enemy was destroyed, a single roll decides which drop it is
+func (w *World) dropPickup(enm *Enemy) {
+	roll := w.rng.Float64()
+	for _, drop := range enm.Drops {
+		roll -= drop.Chance
+		if roll < 0 {
+			w.addPickup(drop.PowerUp, drop.Weapon, enm.X, enm.Y)
+			return
+		}
+	}
+}
+
+// Logic to spawn a random power up or weapon somewhere away from the edges of the screen
+func (w *World) spawnNewPickup() {
+	var pu PowerUp
+	var wk WeaponKind
+	if i := w.rng.Intn(len(PowerUps) + len(WeaponKinds)); i < len(PowerUps) {
+		pu = PowerUps[i]
+	} else {
+		wk = WeaponKinds[i-len(PowerUps)]
+	}
+
+	x := spriteSize + w.rng.Float64()*(ScreenWidth-2*spriteSize)
+	y := spriteSize + w.rng.Float64()*(ScreenHeight-2*spriteSize)
+	w.addPickup(pu, wk, x, y)
+}
+
+// Put a power up or a weapon on the ground, only one of them is set
+func (w *World) addPickup(pu PowerUp, wk WeaponKind, x, y float64) {
+	w.Pickups = append(w.Pickups, &Pickup{
+		PowerUp:   pu,
+		Weapon:    wk,
+		X:         x,
+		Y:         y,
+		Remaining: seconds(w.Config.PickupLifetime),
+	})
+}
+
+func (w *World) checkPowerUpPickups() {
+	w.pickupGrid.Clear()
+	for _, pck := range w.Pickups {
+		w.pickupGrid.Insert(pck.X, pck.Y, pck)
+	}
+
+	for _, p := range w.Players {
+		if p.Caught {
+			continue
+		}
+
+		area := p.pickupArea()
+		w.pickupGrid.Query(p.X, p.Y, area.bound()+pickupHitbox.bound(), func(pck *Pickup) bool {
+			if pck.Remaining > 0 && Overlaps(area, pck.Shape()) {
+				// A weapon replaces the one the player holds
+				if pck.Weapon != "" {
+					p.equip(w.Config.Weapons[pck.Weapon])
+				} else {
+					p.applyPowerUp(w, pck.PowerUp)
+				}
+				// Mark it collected, it is removed below
+				pck.Remaining = 0
+				w.emit(EventPickup)
+			}
+			return true
+		})
+	}
+
+	w.Pickups = slices.DeleteFunc(w.Pickups, func(pck *Pickup) bool { return pck.Remaining <= 0 })
+}
+
+// A drop is either a power up or a weapon
+func (d Drop) validate() error {
+	switch {
+	case d.PowerUp != "" && d.Weapon != "":
+		return fmt.Errorf("a drop is either a power up or a weapon, got %q and %q", d.PowerUp, d.Weapon)
+	case d.Weapon != "":
+		return validateWeapon(d.Weapon)
+	}
+	return validatePowerUp(d.PowerUp)
+}
+
+func validatePowerUp(pu PowerUp) error {
+	for _, known := range PowerUps {
+		if pu == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown power up %q", pu)
+}
@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"math"
+	"time"
+)
+
+type Player struct {
+	BoltAmount   int
+	BoltCapacity int // The most bolts the player can carry
+	Health       int
+	Invulnerable time.Duration // Time left until the player can be hurt again
+	Caught       bool          // Caught players take no further part in the round
+	X, Y         float64       // These address the CENTER of an image
+	Rotation     float64
+	Effects      []Effect // Power ups active on the player, in the order they were collected
+
+	// State of the weapon, timers are counted in ticks
+	WeaponKind WeaponKind
+	weapon     Weapon  // Stats of the weapon in hand, copied from the Config when it was equipped
+	Magazine   int     // Shots left in the quiver
+	Charge     float64 // Charge of the shot being held, from 0 to 1
+	Swing      float64 // Ticks left of the swing being shown
+	FireHeld   bool    // Fire was held on the tick before
+	Shots      int     // Times the weapon was fired in the round
+	cooldown   float64 // Ticks until the weapon can fire again
+	reloading  float64 // Ticks spent refilling the quiver
+}
+
+// Update method of the Player struct
+func (p *Player) Update(w *World, in Input, scale float64) {
+	if p.Caught {
+		return
+	}
+
+	if in.Aiming {
+		p.Rotation = in.Aim
+	}
+
+	speed := w.Config.PlayerSpeed
+	if p.Active(PowerUpSpeed) {
+		speed *= speedBoost
+	}
+	p.X += in.MoveX * speed * scale
+	p.Y += in.MoveY * speed * scale
+
+	// Handle Player shooting, the weapon decides when it can fire
+	p.useWeapon(w, in, scale)
+}
+
+// Take the damage and get knocked away from the point it came from, unless the player was hit just before
+func (p *Player) hurt(w *World, damage int, fromX, fromY, knockback float64) {
+	if p.Invulnerable > 0 {
+		return
+	}
+
+	p.Health -= damage
+	w.emit(EventHurt)
+	if w.Config.OneHit || p.Health <= 0 {
+		p.Health = 0
+		p.Caught = true
+		if w.allCaught() {
+			w.GameOver = true
+		}
+		return
+	}
+
+	p.Invulnerable = seconds(w.Config.Invulnerability)
+
+	// Push the player straight away from where the hit came from, but keep them on the screen
+	dx, dy := p.X-fromX, p.Y-fromY
+	if distance := math.Hypot(dx, dy); distance > 0 {
+		p.X = math.Max(spriteSize/2, math.Min(ScreenWidth-spriteSize/2, p.X+dx/distance*knockback))
+		p.Y = math.Max(spriteSize/2, math.Min(ScreenHeight-spriteSize/2, p.Y+dy/distance*knockback))
+	}
+}
+
+func (p *Player) addBolt() {
+	if p.BoltAmount < p.BoltCapacity {
+		p.BoltAmount += 1
+	}
+}
+
+func (p *Player) removeBolt() {
+	p.BoltAmount -= 1
+}
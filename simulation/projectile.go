@@ -0,0 +1,101 @@
+package simulation
+
+import "math"
+
+// Faction tells who shot a projectile, so that it only hits the other side
+type Faction int
+
+const (
+	FactionPlayer Faction = iota
+	FactionEnemy
+)
+
+type Projectile struct {
+	X, Y                 float64 // These address the CENTER of an image
+	VelocityX, VelocityY float64
+	Rotation             float64
+	Active               bool
+	Faction              Faction
+	Damage               int
+	Pierce               int     // Enemies the projectile can still fly through
+	Bounces              int     // Times the projectile can still bounce off the edges of the screen
+	Friction             float64 // Part of its speed the projectile loses every tick, 0 for none
+	Lifetime             float64 // Ticks left before the projectile stops, 0 for no limit
+	Pickable             bool    // The projectile lies on the ground to be picked up when it stops, otherwise it vanishes
+	removed              bool    // Picked up or gone, it is removed from the world at the end of the check
+}
+
+func (p *Projectile) Update(scale float64) {
+	// Projectiles lying on the ground stay where they are
+	if !p.Active {
+		return
+	}
+
+	// Move the projectile based on its velocity
+	p.X += p.VelocityX * scale
+	p.Y += p.VelocityY * scale
+
+	p.bounce()
+
+	// Friction slows the projectile down until it stops
+	if p.Friction > 0 {
+		slowdown := math.Pow(1-p.Friction, scale)
+		p.VelocityX *= slowdown
+		p.VelocityY *= slowdown
+		if math.Hypot(p.VelocityX, p.VelocityY) < stopSpeed {
+			p.stop()
+			return
+		}
+	}
+
+	if p.Lifetime > 0 {
+		p.Lifetime -= scale
+		if p.Lifetime <= 0 {
+			p.stop()
+		}
+	}
+}
+
+// Mirror the projectile back onto the screen from the edge it flew past, while it has bounces left
+func (p *Projectile) bounce() {
+	if p.Bounces == 0 {
+		return
+	}
+
+	bounced := false
+	if p.X < 0 || p.X > ScreenWidth {
+		p.X = reflect(p.X, ScreenWidth)
+		p.VelocityX = -p.VelocityX
+		bounced = true
+	}
+	if p.Y < 0 || p.Y > ScreenHeight {
+		p.Y = reflect(p.Y, ScreenHeight)
+		p.VelocityY = -p.VelocityY
+		bounced = true
+	}
+	if bounced {
+		p.Bounces--
+		p.Rotation = math.Atan2(p.VelocityY, p.VelocityX)
+	}
+}
+
+func reflect(v, limit float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return 2*limit - v
+}
+
+// Stop the projectile where it is, it either lies there to be picked up or vanishes
+func (p *Projectile) stop() {
+	p.VelocityX, p.VelocityY = 0, 0
+	p.Active = false
+	if !p.Pickable {
+		p.removed = true
+	}
+}
+
+// Tell if the projectile flew off the screen, it is lost then
+func (p *Projectile) offScreen() bool {
+	return p.X < 0 || p.X > ScreenWidth || p.Y < 0 || p.Y > ScreenHeight
+}
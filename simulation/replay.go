@@ -0,0 +1,157 @@
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Replay holds everything needed to play a round again: the seed, the rules and the input of every tick.
+// It also keeps the outcome of the recorded round, so that a replay can verify the simulation still ends the same.
+type Replay struct {
+	Seed   int64      `json:"seed"`
+	Config Config     `json:"config"`
+	Stages StageSet   `json:"stages"`
+	Inputs []InputRun `json:"inputs"`
+
+	Ticks            int  `json:"ticks"`
+	EnemiesDestroyed int  `json:"enemies_destroyed"`
+	Won              bool `json:"won"`
+}
+
+// InputRun is the same input of all the players repeated for Count ticks in a row, it keeps replay files small
+type InputRun struct {
+	Inputs []Input `json:"inputs"`
+	Count  int     `json:"count"`
+}
+
+// Recorder collects the input of a round, call Record once for every Step of the World
+type Recorder struct {
+	replay Replay
+}
+
+func NewRecorder(w *World) *Recorder {
+	return &Recorder{
+		replay: Replay{
+			Seed:   w.Seed,
+			Config: w.Config,
+			Stages: w.Config.Stages,
+		},
+	}
+}
+
+func (r *Recorder) Record(inputs []Input) {
+	rp := &r.replay
+	rp.Ticks++
+
+	// Extend the last run if the input did not change
+	if last := len(rp.Inputs) - 1; last >= 0 && slices.Equal(rp.Inputs[last].Inputs, inputs) {
+		rp.Inputs[last].Count++
+		return
+	}
+	rp.Inputs = append(rp.Inputs, InputRun{Inputs: slices.Clone(inputs), Count: 1})
+}
+
+// Finish returns the replay with the outcome of the given World
+func (r *Recorder) Finish(w *World) *Replay {
+	replay := r.replay
+	replay.EnemiesDestroyed = w.EnemiesDestroyed
+	replay.Won = w.Won()
+	return &replay
+}
+
+// Playback hands out the recorded input one tick at a time
+type Playback struct {
+	inputs []InputRun
+	run    int
+	tick   int
+}
+
+func NewPlayback(replay *Replay) *Playback {
+	return &Playback{inputs: replay.Inputs}
+}
+
+// Next returns the input of all the players for the next tick, or false once the recording has ended
+func (p *Playback) Next() ([]Input, bool) {
+	for p.run < len(p.inputs) {
+		run := p.inputs[p.run]
+		if p.tick < run.Count {
+			p.tick++
+			return run.Inputs, true
+		}
+		p.run++
+		p.tick = 0
+	}
+	return nil, false
+}
+
+// NewReplayWorld creates the World in the state the recorded round started in
+func NewReplayWorld(replay *Replay) *World {
+	cfg := replay.Config
+	cfg.Stages = replay.Stages
+	return NewWorld(cfg, replay.Seed)
+}
+
+// Run plays the whole replay headless and returns the World it ended with
+func (r *Replay) Run() *World {
+	w := NewReplayWorld(r)
+	playback := NewPlayback(r)
+
+	for inputs, ok := playback.Next(); ok; inputs, ok = playback.Next() {
+		w.Step(inputs, TickDuration)
+	}
+
+	return w
+}
+
+// Verify plays the replay and checks that it ends with the recorded outcome
+func (r *Replay) Verify() error {
+	w := r.Run()
+
+	var errs []error
+	if !w.GameOver {
+		errs = append(errs, errors.New("round did not end"))
+	}
+	if w.EnemiesDestroyed != r.EnemiesDestroyed {
+		errs = append(errs, fmt.Errorf("enemies destroyed: recorded %d, got %d", r.EnemiesDestroyed, w.EnemiesDestroyed))
+	}
+	if w.Won() != r.Won {
+		errs = append(errs, fmt.Errorf("won: recorded %t, got %t", r.Won, w.Won()))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Replay) Save(path string) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	return nil
+}
+
+// LoadReplay reads a replay file, its rules are validated the same way as the config and stage files
+func LoadReplay(path string) (*Replay, error) {
+	// Replays recorded before the weapons were in the config play with the default ones
+	replay := &Replay{Config: DefaultConfig()}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	defer file.Close()
+
+	if err := decodeStrict(file, replay); err != nil {
+		return nil, fmt.Errorf("replay %s: %w", path, err)
+	}
+	if err := errors.Join(replay.Config.Validate(), replay.Stages.Validate()); err != nil {
+		return nil, fmt.Errorf("replay %s: %w", path, err)
+	}
+
+	return replay, nil
+}
@@ -0,0 +1,98 @@
+package simulation
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// Record a short round which is won by surviving it, and write it into a file
+func recordTestReplay(t *testing.T) string {
+	t.Helper()
+
+	cfg := DefaultConfig()
+	cfg.Stages = StageSet{
+		Win:   WinSurvive,
+		Types: DefaultEnemyTypes(),
+		Stages: []Stage{
+			{Duration: 8, SpawnInterval: 0.25, Enemies: []EnemySpawn{{Type: "orc"}, {Type: "slime"}}},
+		},
+	}
+	w := NewWorld(cfg, 99)
+	rec := NewRecorder(w)
+
+	for tick := 0; !w.GameOver; tick++ {
+		if tick > 20*TickRate {
+			t.Fatal("the recorded round did not end")
+		}
+		inputs := []Input{aimAtClosest(w, tick)}
+		rec.Record(inputs)
+		w.Step(inputs, TickDuration)
+	}
+	if w.EnemiesDestroyed == 0 {
+		t.Fatal("no enemies were destroyed, a changed replay could end the same")
+	}
+
+	path := filepath.Join(t.TempDir(), "replay.json")
+	if err := rec.Finish(w).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// The player stands still and shoots at the closest enemy
+func aimAtClosest(w *World, tick int) Input {
+	p := w.Players[0]
+	var closest *Enemy
+	for _, enm := range w.Enemies {
+		if closest == nil || math.Hypot(enm.X-p.X, enm.Y-p.Y) < math.Hypot(closest.X-p.X, closest.Y-p.Y) {
+			closest = enm
+		}
+	}
+	if closest == nil {
+		return Input{}
+	}
+	return Input{Aim: math.Atan2(closest.Y-p.Y, closest.X-p.X), Aiming: true, Fire: tick%20 < 10}
+}
+
+func TestReplayVerify(t *testing.T) {
+	path := recordTestReplay(t)
+
+	tests := []struct {
+		name   string
+		change func(*Replay)
+		valid  bool
+	}{
+		{"unchanged", func(*Replay) {}, true},
+		{"changed seed", func(r *Replay) { r.Seed++ }, false},
+		{"never firing", func(r *Replay) {
+			for _, run := range r.Inputs {
+				for i := range run.Inputs {
+					run.Inputs[i].Fire = false
+				}
+			}
+		}, false},
+		{"flipped aim", func(r *Replay) {
+			for _, run := range r.Inputs {
+				for i := range run.Inputs {
+					run.Inputs[i].Aim += math.Pi
+				}
+			}
+		}, false},
+		{"other outcome", func(r *Replay) { r.Won = !r.Won }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, err := LoadReplay(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.change(replay)
+
+			if err := replay.Verify(); (err == nil) != tt.valid {
+				t.Errorf("Verify() = %v, want valid %t", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+)
+
+// Win conditions of a StageSet
+const (
+	WinClear   = "clear"   // Win once the last stage stops spawning and all enemies are destroyed
+	WinSurvive = "survive" // Win once the time of the last stage runs out
+)
+
+// StageSet is the whole course of a round, stages are played one after another
+type StageSet struct {
+	Win    string               `json:"win"`
+	Types  map[string]EnemyType `json:"enemy_types"` // Registry of the enemies the stages pick from
+	Stages []Stage              `json:"stages"`
+	Boss   *Boss                `json:"boss,omitempty"` // Appears when the last stage starts, no boss when left out
+}
+
+type Stage struct {
+	Duration      float64      `json:"duration"`        // Seconds, 0 uses stage_duration from the Config
+	SpawnInterval float64      `json:"spawn_interval"`  // Seconds, 0 uses spawn_interval from the Config
+	Enemies       []EnemySpawn `json:"enemies"`         // Enemy mix, a stage without enemies spawns nothing
+	Music         string       `json:"music,omitempty"` // Track played during the stage, the one before keeps playing when left out. Only used for the sound.
+}
+
+// EnemySpawn is one kind of enemy which can be spawned in a stage
+type EnemySpawn struct {
+	Type   string  `json:"type"`   // Name in the registry of enemy types
+	Weight float64 `json:"weight"` // Relative chance of being picked, 0 counts as 1
+}
+
+// DefaultStages returns the three stages of monsters followed by the final stage without spawning
+func DefaultStages() StageSet {
+	return StageSet{
+		Win:   WinClear,
+		Types: DefaultEnemyTypes(),
+		Stages: []Stage{
+			{Enemies: []EnemySpawn{{Type: "slime", Weight: 2}, {Type: "small_slime", Weight: 1}}, Music: "stage1"},
+			{Enemies: []EnemySpawn{{Type: "orc", Weight: 2}, {Type: "goblin", Weight: 1}}, Music: "stage2"},
+			{Enemies: []EnemySpawn{{Type: "ogre", Weight: 2}, {Type: "shaman", Weight: 1}, {Type: "cultist", Weight: 1}}, Music: "stage3"},
+			{Music: "boss"},
+		},
+		Boss: DefaultBoss(),
+	}
+}
+
+// LoadStages reads a JSON stage file, unknown keys and invalid values are reported as errors
+// The enemy types of the file are added to the built-in ones, replacing those with the same name.
+func LoadStages(path string) (StageSet, error) {
+	stages := StageSet{Types: DefaultEnemyTypes()}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return stages, fmt.Errorf("stages: %w", err)
+	}
+	defer file.Close()
+
+	if err := decodeStrict(file, &stages); err != nil {
+		return stages, fmt.Errorf("stages %s: %w", path, err)
+	}
+	if err := stages.Validate(); err != nil {
+		return stages, fmt.Errorf("stages %s: %w", path, err)
+	}
+
+	return stages, nil
+}
+
+// Validate checks that the stages can be played
+func (s StageSet) Validate() error {
+	var errs []error
+
+	if s.Win != WinClear && s.Win != WinSurvive {
+		errs = append(errs, fmt.Errorf("win must be %q or %q, got %q", WinClear, WinSurvive, s.Win))
+	}
+	if len(s.Stages) == 0 {
+		errs = append(errs, errors.New("at least one stage is required"))
+	}
+
+	for i, stage := range s.Stages {
+		if stage.Duration < 0 {
+			errs = append(errs, fmt.Errorf("stage %d: duration must not be negative, got %v", i+1, stage.Duration))
+		}
+		if stage.SpawnInterval < 0 {
+			errs = append(errs, fmt.Errorf("stage %d: spawn_interval must not be negative, got %v", i+1, stage.SpawnInterval))
+		}
+
+		for j, enm := range stage.Enemies {
+			if _, ok := s.Types[enm.Type]; !ok {
+				errs = append(errs, fmt.Errorf("stage %d enemy %d: unknown enemy type %q", i+1, j+1, enm.Type))
+			}
+			if enm.Weight < 0 {
+				errs = append(errs, fmt.Errorf("stage %d enemy %d: weight must not be negative, got %v", i+1, j+1, enm.Weight))
+			}
+		}
+	}
+
+	// Go through the types in a fixed order, so that the errors are always reported the same way
+	names := make([]string, 0, len(s.Types))
+	for name := range s.Types {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, err := range s.Types[name].validate() {
+			errs = append(errs, fmt.Errorf("enemy type %q: %w", name, err))
+		}
+	}
+	errs = append(errs, validateSplits(s.Types)...)
+
+	if s.Boss != nil {
+		errs = append(errs, s.Boss.validate(s.Types)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s Stage) duration(cfg Config) float64 {
+	if s.Duration == 0 {
+		return cfg.StageDuration
+	}
+	return s.Duration
+}
+
+func (s Stage) spawnInterval(cfg Config) float64 {
+	if s.SpawnInterval == 0 {
+		return cfg.SpawnInterval
+	}
+	return s.SpawnInterval
+}
+
+// Randomly pick one of the enemies of the stage according to their weights, returns the name of its type
+func (s Stage) pickEnemy(rng *rand.Rand) string {
+	total := 0.0
+	for _, enm := range s.Enemies {
+		total += enm.weight()
+	}
+
+	roll := rng.Float64() * total
+	for _, enm := range s.Enemies {
+		roll -= enm.weight()
+		if roll < 0 {
+			return enm.Type
+		}
+	}
+	return s.Enemies[len(s.Enemies)-1].Type
+}
+
+func (e EnemySpawn) weight() float64 {
+	if e.Weight == 0 {
+		return 1
+	}
+	return e.Weight
+}
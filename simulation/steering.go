@@ -0,0 +1,85 @@
+package simulation
+
+import (
+	"math"
+)
+
+// Steering weights decide how an enemy moves, every force is scaled by its weight before they are added up
+type Steering struct {
+	Seek       float64 `json:"seek"`       // Head straight for the player
+	Separation float64 `json:"separation"` // Keep away from neighbouring enemies, so that they do not clump together
+	Flank      float64 `json:"flank"`      // Circle around to the side of the player, fades out when close
+	Arrival    float64 `json:"arrival"`    // Slow down when getting close to the player, from 0 (never) to 1 (a lot)
+}
+
+func DefaultSteering() Steering {
+	return Steering{
+		Seek:       1,
+		Separation: 1.5,
+	}
+}
+
+// steer returns the direction the enemy wants to move in (at most 1 long) and the part of its speed to use
+func (enm *Enemy) steer(w *World, dx, dy, distance float64) (dirX, dirY, speedFactor float64) {
+	s := enm.Steering
+
+	// Seek: normalize the direction vector to the player (up to 1)
+	seekX, seekY := dx/distance, dy/distance
+	dirX += seekX * s.Seek
+	dirY += seekY * s.Seek
+
+	// Flank: move sideways to the player, on the side the enemy was given when spawned
+	if s.Flank != 0 {
+		fade := math.Min(1, distance/flankRadius)
+		dirX += -seekY * enm.flankSide * s.Flank * fade
+		dirY += seekX * enm.flankSide * s.Flank * fade
+	}
+
+	// Separation: push away from every neighbour the closer it is
+	if s.Separation != 0 {
+		sepX, sepY := enm.separation(w)
+		dirX += sepX * s.Separation
+		dirY += sepY * s.Separation
+	}
+
+	// Never move faster than the enemy's speed, whatever the sum of the forces
+	if length := math.Hypot(dirX, dirY); length > 1 {
+		dirX, dirY = dirX/length, dirY/length
+	}
+
+	// Arrival: slow down near the player
+	speedFactor = 1
+	if s.Arrival != 0 && distance < arrivalRadius {
+		slowed := math.Max(minArrivalSpeed, distance/arrivalRadius)
+		speedFactor = 1 - s.Arrival*(1-slowed)
+	}
+
+	return dirX, dirY, speedFactor
+}
+
+func (enm *Enemy) separation(w *World) (x, y float64) {
+	w.enemyGrid.Query(enm.X, enm.Y, separationRadius, func(other *Enemy) bool {
+		if other == enm {
+			return true
+		}
+
+		dx, dy := enm.X-other.X, enm.Y-other.Y
+		distance := math.Hypot(dx, dy)
+		if distance >= separationRadius {
+			return true
+		}
+
+		// Enemies on the very same spot are pushed apart in a fixed direction
+		if distance == 0 {
+			x += enm.flankSide
+			return true
+		}
+
+		strength := 1 - distance/separationRadius
+		x += dx / distance * strength
+		y += dy / distance * strength
+		return true
+	})
+
+	return x, y
+}
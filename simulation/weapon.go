@@ -0,0 +1,313 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// WeaponKind names a Weapon, players start with the one from the Config and swap it for the ones they pick up
+type WeaponKind string
+
+const (
+	WeaponCrossbow WeaponKind = "crossbow" // A single bolt, it can be picked up again where it stops
+	WeaponBow      WeaponKind = "bow"      // A spread of arrows from a quiver which refills over time
+	WeaponStaff    WeaponKind = "staff"    // Hold to charge, the longer the charge the faster and stronger the shot
+	WeaponSword    WeaponKind = "sword"    // Swing at the enemies right in front
+)
+
+// Ammo decides what a weapon uses up when fired
+type Ammo string
+
+const (
+	AmmoBolts  Ammo = "bolts"  // The bolts of the player, they lie on the ground to be picked up after a shot
+	AmmoQuiver Ammo = "quiver" // A magazine of shots which refills once empty, shots vanish when they stop
+	AmmoNone   Ammo = "none"   // Fires as long as the cooldown allows
+)
+
+type Weapon struct {
+	Kind        WeaponKind `json:"-"` // Its key in the WeaponSet
+	Ammo        Ammo       `json:"ammo"`
+	FireRate    float64    `json:"fire_rate"`   // Shots per second at most
+	Projectiles int        `json:"projectiles"` // Fired at once in a fan, 0 for melee
+	Spread      float64    `json:"spread"`      // Radians between the projectiles of the fan
+	Speed       float64    `json:"speed"`       // Multiplier of the projectile_speed from the Config
+	Damage      int        `json:"damage"`
+	Magazine    int        `json:"magazine"` // Shots in a full quiver
+	Reload      float64    `json:"reload"`   // Seconds to refill an empty quiver
+	Charge      float64    `json:"charge"`   // Seconds to fully charge a shot, 0 for weapons which fire on press
+	Reach       float64    `json:"reach"`    // Pixels a melee swing reaches in front of the player
+	Arc         float64    `json:"arc"`      // Radians covered by a melee swing
+	Pierce      int        `json:"pierce"`   // Enemies a projectile flies through before it stops
+	Bounces     int        `json:"bounces"`  // Times a projectile bounces off the edges of the screen
+	Friction    float64    `json:"friction"` // Part of its speed a projectile loses every tick
+	Lifetime    float64    `json:"lifetime"` // Seconds before a projectile stops, 0 for no limit
+	Pickable    bool       `json:"pickable"` // The middle projectile of a shot lies on the ground to be picked up as a bolt
+}
+
+// WeaponKinds lists all the weapons in the order they are shown to the player
+var WeaponKinds = []WeaponKind{WeaponCrossbow, WeaponBow, WeaponStaff, WeaponSword}
+
+// WeaponSet holds the stats of every weapon by its kind
+type WeaponSet map[WeaponKind]Weapon
+
+// DefaultWeapons returns the stats the weapons were designed with
+func DefaultWeapons() WeaponSet {
+	return WeaponSet{
+		WeaponCrossbow: {Kind: WeaponCrossbow, Ammo: AmmoBolts, FireRate: 6, Projectiles: 1, Speed: 1, Damage: 1, Pickable: true},
+		WeaponBow:      {Kind: WeaponBow, Ammo: AmmoQuiver, FireRate: 2, Projectiles: 5, Spread: 0.15, Speed: 0.8, Damage: 1, Magazine: 4, Reload: 2, Lifetime: 1},
+		WeaponStaff:    {Kind: WeaponStaff, Ammo: AmmoNone, FireRate: 2, Projectiles: 1, Speed: 1.2, Damage: 4, Charge: 1.5, Pierce: 2, Bounces: 2},
+		WeaponSword:    {Kind: WeaponSword, Ammo: AmmoNone, FireRate: 3, Damage: 2, Reach: 1.5 * spriteSize, Arc: 2},
+	}
+}
+
+// UnmarshalJSON reads every weapon over the one already in the set, so a file only needs the values it changes
+func (ws *WeaponSet) UnmarshalJSON(data []byte) error {
+	var raw map[WeaponKind]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if *ws == nil {
+		*ws = WeaponSet{}
+	}
+
+	for kind, value := range raw {
+		wp := (*ws)[kind]
+		wp.Kind = kind
+		if err := decodeStrict(bytes.NewReader(value), &wp); err != nil {
+			return fmt.Errorf("%s: %w", kind, err)
+		}
+		(*ws)[kind] = wp
+	}
+	return nil
+}
+
+// Weapon returns the weapon the player holds
+func (p *Player) Weapon() Weapon {
+	return p.weapon
+}
+
+// Take the weapon in hand with a full quiver
+func (p *Player) equip(wp Weapon) {
+	p.WeaponKind = wp.Kind
+	p.weapon = wp
+	p.Magazine = wp.Magazine
+	p.reloading, p.cooldown, p.Charge = 0, 0, 0
+}
+
+// Fire the weapon according to the fire button, all timers are counted in ticks
+func (p *Player) useWeapon(w *World, in Input, scale float64) {
+	wp := p.Weapon()
+	pressed := in.Fire && !p.FireHeld
+	released := !in.Fire && p.FireHeld
+	p.FireHeld = in.Fire
+
+	p.cooldown = math.Max(0, p.cooldown-scale)
+	p.Swing = math.Max(0, p.Swing-scale)
+
+	// An empty quiver refills on its own
+	if wp.Ammo == AmmoQuiver && p.Magazine == 0 {
+		p.reloading += scale
+		if p.reloading >= wp.Reload*TickRate {
+			p.Magazine = wp.Magazine
+			p.reloading = 0
+		}
+	}
+
+	if wp.Charge == 0 {
+		if pressed && p.canFire(wp) {
+			p.fire(w, wp, 1)
+		}
+		return
+	}
+
+	// Charging weapons fire when the button is let go
+	if in.Fire && p.canFire(wp) {
+		p.Charge = math.Min(1, p.Charge+scale/(wp.Charge*TickRate))
+	}
+	if released && p.Charge > 0 {
+		p.fire(w, wp, math.Max(minCharge, p.Charge))
+		p.Charge = 0
+	}
+}
+
+func (p *Player) canFire(wp Weapon) bool {
+	if p.cooldown > 0 {
+		return false
+	}
+	switch wp.Ammo {
+	case AmmoBolts:
+		return p.BoltAmount > 0
+	case AmmoQuiver:
+		return p.Magazine > 0
+	}
+	return true
+}
+
+// Fire the weapon with the given power, from 0 to 1, which only charged shots use
+func (p *Player) fire(w *World, wp Weapon, power float64) {
+	p.cooldown = TickRate / wp.FireRate
+	p.Shots++
+
+	switch wp.Ammo {
+	case AmmoBolts:
+		p.removeBolt()
+	case AmmoQuiver:
+		p.Magazine--
+	}
+
+	w.emit(EventShot)
+	if wp.Projectiles == 0 {
+		p.swing(w, wp)
+		return
+	}
+	p.Shoot(w, wp, power)
+}
+
+// Shoot a fan of projectiles in the direction the player is aiming
+func (p *Player) Shoot(w *World, wp Weapon, power float64) {
+	pierce := wp.Pierce
+	if p.Active(PowerUpPiercing) {
+		pierce += piercingEnemies
+	}
+
+	// With multishot extra projectiles fly to both sides
+	projectiles := wp.Projectiles
+	if p.Active(PowerUpMultishot) {
+		projectiles += multishotBolts - 1
+	}
+
+	spread := wp.Spread
+	if spread == 0 {
+		spread = multishotSpread
+	}
+
+	speed := w.Config.ProjectileSpeed * wp.Speed * power
+	damage := max(1, int(math.Round(float64(wp.Damage)*power)))
+
+	for i := 0; i < projectiles; i++ {
+		offset := float64(i) - float64(projectiles-1)/2
+		rotation := p.Rotation + offset*spread
+
+		// Only the middle bolt is given back (the right one of the two in the middle of an even fan), the others
+		// vanish when they stop so that no bolts are made out of nothing
+		pickable := wp.Pickable && i == projectiles/2
+
+		w.Projectiles = append(w.Projectiles, &Projectile{
+			X:         p.X,
+			Y:         p.Y,
+			Rotation:  rotation,
+			VelocityX: math.Cos(rotation) * speed,
+			VelocityY: math.Sin(rotation) * speed,
+			Active:    true,
+			Faction:   FactionPlayer,
+			Damage:    damage,
+			Pierce:    pierce,
+			Bounces:   wp.Bounces,
+			Friction:  wp.Friction,
+			Lifetime:  wp.Lifetime * TickRate,
+			Pickable:  pickable,
+		})
+	}
+}
+
+// Hit every enemy in the arc in front of the player
+func (p *Player) swing(w *World, wp Weapon) {
+	p.Swing = swingTime * TickRate
+
+	area := Circle{X: p.X, Y: p.Y, Radius: wp.Reach}
+	w.fillEnemyGrid()
+	w.enemyGrid.Query(p.X, p.Y, wp.Reach+w.enemyBound, func(enm *Enemy) bool {
+		if enm.destroyed || !Overlaps(area, enm.Shape()) {
+			return true
+		}
+
+		// Only the enemies on the side the player is facing
+		angle := math.Atan2(enm.Y-p.Y, enm.X-p.X) - p.Rotation
+		angle = math.Remainder(angle, 2*math.Pi)
+		if math.Abs(angle) <= wp.Arc/2 {
+			w.damageEnemy(enm, wp.Damage)
+		}
+		return true
+	})
+}
+
+// SwingProgress tells how far the swing being shown has gone, from 0 at its start to 1 at its end
+func (p *Player) SwingProgress() float64 {
+	return 1 - p.Swing/(swingTime*TickRate)
+}
+
+func validateWeapon(kind WeaponKind) error {
+	if !slices.Contains(WeaponKinds, kind) {
+		return fmt.Errorf("unknown weapon %q", kind)
+	}
+	return nil
+}
+
+// Check the stats of every weapon, all of them have to be there
+func (ws WeaponSet) validate() []error {
+	var errs []error
+	for _, kind := range WeaponKinds {
+		if _, ok := ws[kind]; !ok {
+			errs = append(errs, fmt.Errorf("weapons: %q is missing", kind))
+		}
+	}
+	for kind, wp := range ws {
+		if err := validateWeapon(kind); err != nil {
+			errs = append(errs, fmt.Errorf("weapons: %w", err))
+			continue
+		}
+		for _, err := range wp.validate() {
+			errs = append(errs, fmt.Errorf("weapons.%s: %w", kind, err))
+		}
+	}
+	return errs
+}
+
+func (wp Weapon) validate() []error {
+	var errs []error
+
+	switch wp.Ammo {
+	case AmmoBolts, AmmoQuiver, AmmoNone:
+	default:
+		errs = append(errs, fmt.Errorf("unknown ammo %q, expected %q, %q or %q", wp.Ammo, AmmoBolts, AmmoQuiver, AmmoNone))
+	}
+	if wp.FireRate <= 0 || wp.FireRate > TickRate {
+		errs = append(errs, fmt.Errorf("fire_rate must be above 0 and at most %d, got %v", TickRate, wp.FireRate))
+	}
+	if wp.Projectiles < 0 || wp.Projectiles > 50 {
+		errs = append(errs, fmt.Errorf("projectiles must be between 0 and 50, got %d", wp.Projectiles))
+	}
+	if wp.Damage < 0 {
+		errs = append(errs, fmt.Errorf("damage must not be negative, got %d", wp.Damage))
+	}
+	if wp.Spread < 0 || wp.Reload < 0 || wp.Charge < 0 || wp.Lifetime < 0 {
+		errs = append(errs, fmt.Errorf("spread, reload, charge and lifetime must not be negative, got %v, %v, %v and %v", wp.Spread, wp.Reload, wp.Charge, wp.Lifetime))
+	}
+	if wp.Pierce < 0 || wp.Bounces < 0 {
+		errs = append(errs, fmt.Errorf("pierce and bounces must not be negative, got %d and %d", wp.Pierce, wp.Bounces))
+	}
+	if wp.Friction < 0 || wp.Friction >= 1 {
+		errs = append(errs, fmt.Errorf("friction must be at least 0 and below 1, got %v", wp.Friction))
+	}
+
+	// A weapon either shoots or swings
+	if wp.Projectiles > 0 && wp.Speed <= 0 {
+		errs = append(errs, fmt.Errorf("speed must be positive for a weapon with projectiles, got %v", wp.Speed))
+	}
+	if wp.Projectiles == 0 && (wp.Reach <= 0 || wp.Arc <= 0) {
+		errs = append(errs, fmt.Errorf("reach and arc must be positive for a weapon without projectiles, got %v and %v", wp.Reach, wp.Arc))
+	}
+	if wp.Ammo == AmmoQuiver && wp.Magazine <= 0 {
+		errs = append(errs, fmt.Errorf("magazine must be positive for a quiver, got %d", wp.Magazine))
+	}
+
+	// Picking a projectile up gives a bolt back, so only weapons using bolts may leave them around
+	if wp.Pickable && wp.Ammo != AmmoBolts {
+		errs = append(errs, fmt.Errorf("only weapons using %q can be pickable", AmmoBolts))
+	}
+
+	return errs
+}
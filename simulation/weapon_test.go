@@ -0,0 +1,65 @@
+package simulation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWeaponValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Weapon)
+		valid  bool
+	}{
+		{"default", func(*Weapon) {}, true},
+		{"unknown ammo", func(wp *Weapon) { wp.Ammo = "arrows" }, false},
+		{"no fire rate", func(wp *Weapon) { wp.FireRate = 0 }, false},
+		{"negative bounces", func(wp *Weapon) { wp.Bounces = -1 }, false},
+		{"negative pierce", func(wp *Weapon) { wp.Pierce = -1 }, false},
+		{"full friction", func(wp *Weapon) { wp.Friction = 1 }, false},
+		{"negative lifetime", func(wp *Weapon) { wp.Lifetime = -1 }, false},
+		{"quiver without magazine", func(wp *Weapon) { wp.Ammo = AmmoQuiver }, false},
+		{"pickable quiver", func(wp *Weapon) { wp.Ammo, wp.Magazine = AmmoQuiver, 4 }, false},
+		{"melee without reach", func(wp *Weapon) { wp.Projectiles = 0 }, false},
+		{"melee", func(wp *Weapon) { wp.Projectiles, wp.Pickable, wp.Reach, wp.Arc = 0, false, 40, 1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp := DefaultWeapons()[WeaponCrossbow]
+			tt.change(&wp)
+			if errs := wp.validate(); (len(errs) == 0) != tt.valid {
+				t.Errorf("validate() = %v, want valid %t", errs, tt.valid)
+			}
+		})
+	}
+}
+
+// Every shot of a pickable weapon gives exactly one bolt back, whatever the size of the fan
+func TestShootLeavesOnePickable(t *testing.T) {
+	for projectiles := 1; projectiles <= 4; projectiles++ {
+		for _, multishot := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%d projectiles multishot %t", projectiles, multishot), func(t *testing.T) {
+				w := NewWorld(DefaultConfig(), 1)
+				p := w.Players[0]
+				if multishot {
+					p.Effects = []Effect{{PowerUp: PowerUpMultishot, Remaining: time.Second}}
+				}
+				wp := DefaultWeapons()[WeaponCrossbow]
+				wp.Projectiles, wp.Spread = projectiles, 0.1
+
+				p.Shoot(w, wp, 1)
+				pickable := 0
+				for _, prj := range w.Projectiles {
+					if prj.Pickable {
+						pickable++
+					}
+				}
+				if pickable != 1 {
+					t.Errorf("%d of %d projectiles are pickable, want 1", pickable, len(w.Projectiles))
+				}
+			})
+		}
+	}
+}
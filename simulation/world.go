@@ -0,0 +1,406 @@
+// Package simulation contains the rules of a single round of the game: movement of the Player,
+// Enemy and Projectile, collisions, pickups and stages. It has no rendering or input dependency,
+// so it can be advanced headless one fixed tick at a time with Step.
+package simulation
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// Input is everything a single player can do during a single tick
+type Input struct {
+	MoveX  float64 `json:"move_x,omitempty"` // Movement direction, each axis in range -1 to 1
+	MoveY  float64 `json:"move_y,omitempty"`
+	Aim    float64 `json:"aim,omitempty"`    // Aiming angle in radians
+	Aiming bool    `json:"aiming,omitempty"` // Aim is only applied when this is set, otherwise the previous angle is kept
+	Fire   bool    `json:"fire,omitempty"`   // Held state of the fire button
+	Pause  bool    `json:"pause,omitempty"`  // Toggles the pause on the tick it is set, any player can pause
+}
+
+// MaxPlayers is the most players which can play a round together
+const MaxPlayers = 4
+
+type World struct {
+	Config           Config
+	Enemies          []*Enemy
+	Projectiles      []*Projectile
+	Pickups          []*Pickup
+	Players          []*Player
+	EnemiesDestroyed int
+	Score            int           // Points of the destroyed enemies
+	Stage            int           // Number of the current stage, counted from 1
+	Elapsed          time.Duration // Time spent in game, excluding pauses
+	GameOver         bool
+	Paused           bool
+	Seed             int64   // Seed of the random number generator, the same seed and input give the same round
+	Events           []Event // What happened during the last Step, cleared by the next one
+	rng              *rand.Rand
+	enemyGrid        *Grid[*Enemy]      // Broad phase for anything looking for enemies
+	enemyBound       float64            // Largest bound of the enemy hitboxes in the grid
+	boss             *Enemy             // Kept after it is destroyed, to know the round is won
+	boltGrid         *Grid[*Projectile] // Projectiles lying on the ground
+	pickupGrid       *Grid[*Pickup]
+	won              bool
+	lastSpawn        time.Duration
+	lastPickupSpawn  time.Duration
+	stageStart       time.Duration
+}
+
+func NewWorld(cfg Config, seed int64) *World {
+	w := &World{Config: cfg}
+	w.Reset(seed)
+	return w
+}
+
+// Step advances the world by dt using the input of every player, in the order of Players
+func (w *World) Step(inputs []Input, dt time.Duration) {
+	w.Events = w.Events[:0]
+	if w.GameOver {
+		return
+	}
+
+	for _, in := range inputs {
+		if in.Pause {
+			w.Paused = !w.Paused
+			break
+		}
+	}
+	if w.Paused {
+		return
+	}
+
+	w.Elapsed += dt
+
+	// Speeds are defined per tick, so scale them in case dt is not a single tick
+	scale := dt.Seconds() * TickRate
+
+	// Update Players, the ones without input this tick stand still
+	for i, p := range w.Players {
+		in := Input{}
+		if i < len(inputs) {
+			in = inputs[i]
+		}
+		p.Update(w, in, scale)
+		p.updateEffects(dt)
+		p.Invulnerable = max(0, p.Invulnerable-dt)
+	}
+
+	// Update all Enemies, the grid lets them find their neighbours quickly
+	w.fillEnemyGrid()
+	for _, enm := range w.Enemies {
+		enm.Update(w, scale)
+	}
+	w.Enemies = slices.DeleteFunc(w.Enemies, func(enm *Enemy) bool { return enm.destroyed })
+
+	// Update all Projectiles, the ones out of the screen without bounces left are lost
+	for _, prj := range w.Projectiles {
+		prj.Update(scale)
+	}
+	w.Projectiles = slices.DeleteFunc(w.Projectiles, func(prj *Projectile) bool { return prj.removed || prj.offScreen() })
+
+	// Check for collisions between Projectiles and Enemies, then the shots of the Enemies against the Players
+	w.checkCollisions()
+	w.checkEnemyShots()
+
+	// Check for projectile pickup by the Players
+	w.checkPickups()
+
+	// Let the pickups vanish after a while and check if the Players collect any
+	for i := len(w.Pickups) - 1; i >= 0; i-- {
+		pck := w.Pickups[i]
+		pck.Update(dt)
+		if pck.Remaining <= 0 {
+			w.Pickups = append(w.Pickups[:i], w.Pickups[i+1:]...)
+		}
+	}
+	w.checkPowerUpPickups()
+
+	// Check if it's time for a pickup to appear on its own
+	if w.Config.PickupInterval > 0 && w.Elapsed-w.lastPickupSpawn >= seconds(w.Config.PickupInterval) {
+		w.spawnNewPickup()
+		w.lastPickupSpawn = w.Elapsed
+	}
+
+	// Check if it's time to spawn a new enemy and the stage still spawns any
+	stage := w.currentStage()
+	if !w.spawningOver() && w.Elapsed-w.lastSpawn >= seconds(stage.spawnInterval(w.Config)) {
+		w.spawnNewEnemy()
+
+		// Reset the timer for next spawn
+		w.lastSpawn = w.Elapsed
+	}
+
+	w.controlGameStage()
+
+	if w.GameOver {
+		if w.won {
+			w.emit(EventWon)
+		} else {
+			w.emit(EventGameOver)
+		}
+	}
+}
+
+// Custom functions with a World receiver below
+
+// Reset puts the world into the state of a freshly started round played with the given seed
+func (w *World) Reset(seed int64) {
+	*w = World{
+		Config:     w.Config,
+		Seed:       seed,
+		rng:        rand.New(rand.NewSource(seed)),
+		enemyGrid:  NewGrid[*Enemy](gridCell),
+		boltGrid:   NewGrid[*Projectile](gridCell),
+		pickupGrid: NewGrid[*Pickup](gridCell),
+		Stage:      1,
+	}
+
+	// Players stand next to each other around the center of the screen
+	for i := 0; i < w.Config.Players; i++ {
+		offset := (float64(i) - float64(w.Config.Players-1)/2) * 2 * spriteSize
+		p := &Player{
+			BoltAmount:   w.Config.InitialBoltAmount,
+			BoltCapacity: w.Config.InitialBoltAmount,
+			Health:       w.Config.PlayerHealth,
+			X:            ScreenWidth/2 + offset,
+			Y:            ScreenHeight / 2,
+		}
+		p.equip(w.Config.Weapons[w.Config.Weapon])
+		w.Players = append(w.Players, p)
+	}
+}
+
+// Won reports whether the round ended by meeting the win condition of the stages
+func (w *World) Won() bool {
+	return w.GameOver && w.won
+}
+
+// Logic to spawn an enemy
+func (w *World) spawnNewEnemy() {
+	name := w.currentStage().pickEnemy(w.rng)
+
+	// Randomly choose an edge (0=left, 1=top, 2=right, 3=bottom), the enemy starts just out of the screen
+	var x, y float64
+	edge := w.rng.Intn(4)
+	switch edge {
+	case 0:
+		// Left edge
+		x = -spriteSize * 1.5
+		y = w.rng.Float64()*ScreenHeight + spriteSize/2
+	case 1:
+		// Top edge
+		x = w.rng.Float64()*ScreenWidth + spriteSize/2
+		y = -spriteSize * 1.5
+	case 2:
+		// Right edge
+		x = ScreenWidth + spriteSize/2
+		y = w.rng.Float64()*ScreenHeight + spriteSize/2
+	case 3:
+		// Bottom edge
+		x = w.rng.Float64()*ScreenWidth + spriteSize/2
+		y = ScreenHeight + spriteSize/2
+	}
+
+	// Add the new enemy to the Enemies slice
+	w.Enemies = append(w.Enemies, w.newEnemy(name, x, y))
+}
+
+// Put every enemy into the grid at its current position
+func (w *World) fillEnemyGrid() {
+	w.enemyGrid.Clear()
+	w.enemyBound = 0
+	for _, enm := range w.Enemies {
+		w.enemyGrid.Insert(enm.X, enm.Y, enm)
+		w.enemyBound = math.Max(w.enemyBound, enm.Hitbox.bound())
+	}
+}
+
+func (w *World) checkCollisions() {
+	// The enemies moved since the grid was filled
+	w.fillEnemyGrid()
+
+	for _, prj := range w.Projectiles {
+		// If the projectile is laying on the ground then don't kill enemies with it, enemies don't hit each other
+		if !prj.Active || prj.Faction != FactionPlayer {
+			continue
+		}
+
+		// Only the enemies around the projectile can be hit
+		var hit *Enemy
+		shape := prj.Shape()
+		w.enemyGrid.Query(prj.X, prj.Y, shape.bound()+w.enemyBound, func(enm *Enemy) bool {
+			if !enm.destroyed && Overlaps(shape, enm.Shape()) {
+				hit = enm
+				return false
+			}
+			return true
+		})
+		if hit == nil {
+			continue
+		}
+
+		w.damageEnemy(hit, prj.Damage)
+
+		// Piercing projectiles keep flying
+		if prj.Pierce > 0 {
+			prj.Pierce--
+			continue
+		}
+
+		prj.stop()
+	}
+
+	w.Enemies = slices.DeleteFunc(w.Enemies, func(enm *Enemy) bool { return enm.destroyed })
+	w.Projectiles = slices.DeleteFunc(w.Projectiles, func(prj *Projectile) bool { return prj.removed })
+}
+
+// Take the damage from the health of the enemy, tough enemies take a few hits before they are destroyed
+func (w *World) damageEnemy(enm *Enemy, damage int) {
+	enm.Health -= damage
+	if enm.Health <= 0 {
+		w.enemyDestroyed(enm)
+		return
+	}
+	w.emit(EventHit)
+}
+
+// Count the destroyed enemy, maybe leave a pickup or smaller enemies in its place. It is only marked
+// as destroyed, removing it is up to the caller.
+func (w *World) enemyDestroyed(enm *Enemy) {
+	enm.destroyed = true
+	w.emit(EventDestroyed)
+	w.EnemiesDestroyed++
+	w.Score += enm.Score
+	w.dropPickup(enm)
+	w.splitEnemy(enm)
+}
+
+func (w *World) checkPickups() {
+	// Don't consider the flying ones for pickup, nor the shots of the enemies
+	w.boltGrid.Clear()
+	for _, prj := range w.Projectiles {
+		if !prj.Active && prj.Faction == FactionPlayer {
+			w.boltGrid.Insert(prj.X, prj.Y, prj)
+		}
+	}
+
+	for _, p := range w.Players {
+		if p.Caught {
+			continue
+		}
+
+		area := p.pickupArea()
+		w.boltGrid.Query(p.X, p.Y, area.bound()+projectileHitbox.bound(), func(prj *Projectile) bool {
+			if !prj.removed && Overlaps(area, prj.Shape()) {
+				p.addBolt()
+				prj.removed = true
+				w.emit(EventPickup)
+			}
+			return true
+		})
+	}
+
+	w.Projectiles = slices.DeleteFunc(w.Projectiles, func(prj *Projectile) bool { return prj.removed })
+}
+
+// Find the player who is not caught yet and is the closest to the given point
+func (w *World) nearestPlayer(x, y float64) *Player {
+	var nearest *Player
+	nearestDistance := math.Inf(1)
+
+	for _, p := range w.Players {
+		if p.Caught {
+			continue
+		}
+		if distance := math.Hypot(p.X-x, p.Y-y); distance < nearestDistance {
+			nearest, nearestDistance = p, distance
+		}
+	}
+
+	return nearest
+}
+
+// The round is lost only once every player has been caught
+func (w *World) allCaught() bool {
+	for _, p := range w.Players {
+		if !p.Caught {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *World) controlGameStage() {
+	lastStage := w.Stage == len(w.Config.Stages.Stages)
+	stageOver := w.Elapsed-w.stageStart > seconds(w.currentStage().duration(w.Config))
+
+	// If the stage duration has passed move on to the next one
+	if !lastStage {
+		if stageOver {
+			w.Stage++
+			w.stageStart = w.Elapsed
+			w.emit(EventStage)
+		}
+		return
+	}
+
+	// The boss comes with the last stage and the round is only won by destroying it
+	if w.Config.Stages.Boss != nil {
+		if w.boss == nil {
+			w.spawnBoss()
+		}
+		if w.boss.destroyed {
+			// You win
+			w.won = true
+			w.GameOver = true
+		}
+		return
+	}
+
+	switch w.Config.Stages.Win {
+	case WinClear:
+		if w.spawningOver() && len(w.Enemies) == 0 {
+			// You win
+			w.won = true
+			w.GameOver = true
+		}
+	case WinSurvive:
+		if stageOver {
+			// You win
+			w.won = true
+			w.GameOver = true
+		}
+	}
+}
+
+// StageTime returns the time spent in the current stage and how long it lasts
+func (w *World) StageTime() (elapsed, duration time.Duration) {
+	return w.Elapsed - w.stageStart, seconds(w.currentStage().duration(w.Config))
+}
+
+// EnemyCells calls fn for every cell of the enemy grid with enemies in it, as it was at the end of the last tick
+func (w *World) EnemyCells(fn func(x, y, size float64, count int)) {
+	w.enemyGrid.Cells(fn)
+}
+
+func (w *World) currentStage() Stage {
+	return w.Config.Stages.Stages[w.Stage-1]
+}
+
+// Enemies stop spawning in the last stage once its time runs out, or in any stage without enemies
+func (w *World) spawningOver() bool {
+	stage := w.currentStage()
+	if len(stage.Enemies) == 0 {
+		return true
+	}
+	lastStage := w.Stage == len(w.Config.Stages.Stages)
+	return lastStage && w.Elapsed-w.stageStart > seconds(stage.duration(w.Config))
+}
+
+// Convert a duration given in seconds, as used in the Config, to time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
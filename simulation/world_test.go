@@ -0,0 +1,208 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+// What the tests compare of a World, everything in it is comparable so snapshots can be checked with ==
+type worldSnapshot struct {
+	enemies     []enemySnapshot
+	projectiles []projectileSnapshot
+	destroyed   int
+	score       int
+	gameOver    bool
+}
+
+type enemySnapshot struct {
+	kind   string
+	x, y   float64
+	health int
+}
+
+type projectileSnapshot struct {
+	x, y   float64
+	active bool
+}
+
+func snapshot(w *World) worldSnapshot {
+	s := worldSnapshot{destroyed: w.EnemiesDestroyed, score: w.Score, gameOver: w.GameOver}
+	for _, enm := range w.Enemies {
+		s.enemies = append(s.enemies, enemySnapshot{enm.Type, enm.X, enm.Y, enm.Health})
+	}
+	for _, prj := range w.Projectiles {
+		s.projectiles = append(s.projectiles, projectileSnapshot{prj.X, prj.Y, prj.Active})
+	}
+	return s
+}
+
+func (s worldSnapshot) equal(other worldSnapshot) bool {
+	return slices.Equal(s.enemies, other.enemies) && slices.Equal(s.projectiles, other.projectiles) &&
+		s.destroyed == other.destroyed && s.score == other.score && s.gameOver == other.gameOver
+}
+
+// The players walk in circles, turn their aim around and keep firing
+func scriptedInputs(tick, players int) []Input {
+	inputs := make([]Input, players)
+	for i := range inputs {
+		angle := float64(tick+40*i) / 30
+		inputs[i] = Input{
+			MoveX:  math.Cos(angle),
+			MoveY:  math.Sin(angle),
+			Aim:    -angle,
+			Aiming: true,
+			Fire:   tick%20 < 10,
+		}
+	}
+	return inputs
+}
+
+// Step the world for the given ticks, with the scripted input or none at all
+func run(w *World, ticks int, scripted bool) {
+	for tick := 0; tick < ticks; tick++ {
+		var inputs []Input
+		if scripted {
+			inputs = scriptedInputs(tick, len(w.Players))
+		}
+		w.Step(inputs, TickDuration)
+	}
+}
+
+// Worlds stepped in turns end the same as worlds stepped on their own, so nothing is shared between them
+func TestWorldsDoNotShareState(t *testing.T) {
+	const ticks = 600
+	a, b := NewWorld(DefaultConfig(), 7), NewWorld(DefaultConfig(), 7)
+	for tick := 0; tick < ticks; tick++ {
+		a.Step(scriptedInputs(tick, len(a.Players)), TickDuration)
+		b.Step(nil, TickDuration)
+	}
+
+	aloneA, aloneB := NewWorld(DefaultConfig(), 7), NewWorld(DefaultConfig(), 7)
+	run(aloneA, ticks, true)
+	run(aloneB, ticks, false)
+
+	if !snapshot(a).equal(snapshot(aloneA)) {
+		t.Error("world stepped next to another differs from the one stepped alone")
+	}
+	if !snapshot(b).equal(snapshot(aloneB)) {
+		t.Error("idle world stepped next to another differs from the one stepped alone")
+	}
+	if snapshot(a).equal(snapshot(b)) {
+		t.Error("worlds with different input ended the same, the test proves nothing")
+	}
+}
+
+// The enemy filling the benchmark, a chaser which does not split
+const benchmarkEnemy = "orc"
+
+// BenchmarkStep measures a single Step with many enemies and projectiles in the world, the last case is the
+// worst one to aim for. Whatever gets destroyed or leaves the screen is replaced between the measured ticks,
+// so the load stays the same for the whole run.
+func BenchmarkStep(b *testing.B) {
+	for _, enemies := range []int{250, 500, 1000} {
+		projectiles := enemies / 2
+		b.Run(fmt.Sprintf("%d enemies %d projectiles", enemies, projectiles), func(b *testing.B) {
+			w := newBenchmarkWorld()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				w.fillBenchmark(enemies, projectiles)
+				b.StartTimer()
+
+				w.Step(nil, TickDuration)
+			}
+		})
+	}
+}
+
+// A world which keeps spawning out of the measurement and its player alive, enemies reaching them are replaced
+func newBenchmarkWorld() *World {
+	cfg := DefaultConfig()
+	cfg.PickupInterval = 0
+	w := NewWorld(cfg, 1)
+
+	w.Config.Stages = StageSet{Win: WinSurvive, Types: DefaultEnemyTypes(), Stages: []Stage{{Duration: 1e6}}}
+	w.Players[0].Effects = []Effect{{PowerUp: PowerUpShield, Remaining: time.Duration(math.MaxInt64)}}
+	return w
+}
+
+// Top up the world with enemies spread over the screen and projectiles flying or lying around
+func (w *World) fillBenchmark(enemies, projectiles int) {
+	for len(w.Enemies) < enemies {
+		x, y := w.rng.Float64()*ScreenWidth, w.rng.Float64()*ScreenHeight
+		w.Enemies = append(w.Enemies, w.newEnemy(benchmarkEnemy, x, y))
+	}
+
+	for len(w.Projectiles) < projectiles {
+		angle := w.rng.Float64() * 2 * math.Pi
+		prj := &Projectile{
+			X:        w.rng.Float64() * ScreenWidth,
+			Y:        w.rng.Float64() * ScreenHeight,
+			Active:   w.rng.Intn(2) == 0,
+			Damage:   1,
+			Pickable: true,
+		}
+		if prj.Active {
+			prj.VelocityX = math.Cos(angle) * w.Config.ProjectileSpeed
+			prj.VelocityY = math.Sin(angle) * w.Config.ProjectileSpeed
+		}
+		w.Projectiles = append(w.Projectiles, prj)
+	}
+}
+
+func TestSameSeedAndInputGiveTheSameRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		seed     int64
+		players  int
+		scripted bool
+	}{
+		{"idle", 1, 1, false},
+		{"shooting", 42, 1, true},
+		{"two players", 12345, 2, true},
+		{"four players", -3, 4, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Players = tt.players
+			a, b := NewWorld(cfg, tt.seed), NewWorld(cfg, tt.seed)
+
+			// Compare along the way too, a difference is easier to find close to where it started
+			for i := 0; i < 10; i++ {
+				run(a, 120, tt.scripted)
+				run(b, 120, tt.scripted)
+				if !snapshot(a).equal(snapshot(b)) {
+					t.Fatalf("worlds differ after %d ticks", (i+1)*120)
+				}
+			}
+			if len(a.Enemies) == 0 && a.EnemiesDestroyed == 0 {
+				t.Error("no enemies were spawned, the test proves nothing")
+			}
+		})
+	}
+}
+
+func TestDifferentSeedsSpawnDifferently(t *testing.T) {
+	tests := []struct {
+		a, b int64
+	}{
+		{1, 2},
+		{42, 43},
+		{0, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d and %d", tt.a, tt.b), func(t *testing.T) {
+			a, b := NewWorld(DefaultConfig(), tt.a), NewWorld(DefaultConfig(), tt.b)
+			run(a, 300, false)
+			run(b, 300, false)
+			if snapshot(a).equal(snapshot(b)) {
+				t.Error("enemies spawned the same with different seeds")
+			}
+		})
+	}
+}